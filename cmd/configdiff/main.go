@@ -0,0 +1,83 @@
+// Command configdiff compares the dotenv files of two environments, e.g.
+//
+//	go run github.com/Raj63/go-sdk/cmd/configdiff staging production
+//
+// The files are loaded the same way as config.LoadDotenv, including the SOPS decryption, and the
+// keys that are missing, empty or different are reported with the secrets masked. It exits with
+// the status 1 when a key is missing from an environment, or has another status of the -fail-on
+// flag, so it can gate a release while the values naturally differ, e.g. the DB URIs.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	sdkconfig "github.com/Raj63/go-sdk/config"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("configdiff: ")
+
+	configs := flag.String("configs", "configs", "the directory of the dotenv files")
+	sops := flag.String("sops", ".sops.yaml", "the path of the SOPS config file")
+	secrets := flag.String("secrets", "", "the comma-separated keys to mask, on top of the ones that look like secrets")
+	format := flag.String("format", "table", "the output format, table or json")
+	failOn := flag.String("fail-on", "missing_left,missing_right", "the comma-separated statuses that exit with the status 1, among missing_left, missing_right, empty_left, empty_right and changed")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: configdiff [flags] <left env> <right env>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 || (*format != "table" && *format != "json") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var secretKeys []string
+	if *secrets != "" {
+		secretKeys = strings.Split(*secrets, ",")
+	}
+
+	left, right := flag.Arg(0), flag.Arg(1)
+	diff, err := sdkconfig.DiffEnvs(os.DirFS("."), map[string]string{
+		"configs": *configs,
+		"sops":    *sops,
+	}, left, right, secretKeys...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *format == "json" {
+		out, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(string(out))
+	} else if len(diff) > 0 {
+		fmt.Print(diff.Table(left, right))
+	}
+
+	failing := map[sdkconfig.DiffStatus]bool{}
+	for _, status := range strings.Split(*failOn, ",") {
+		switch status := sdkconfig.DiffStatus(strings.TrimSpace(status)); status {
+		case sdkconfig.DiffMissingLeft, sdkconfig.DiffMissingRight, sdkconfig.DiffEmptyLeft, sdkconfig.DiffEmptyRight, sdkconfig.DiffChanged:
+			failing[status] = true
+		case "":
+		default:
+			log.Printf("unknown status %q in -fail-on", status)
+			flag.Usage()
+			os.Exit(2)
+		}
+	}
+	for _, entry := range diff {
+		if failing[entry.Status] {
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Command configdoc generates the Markdown reference and the `.env.example` file of a config
+// struct from its `env`, `envDefault`, `validate` and `secret` tags and its field doc comments.
+//
+// It must run within the Go module of the config struct, e.g. with go:generate:
+//
+//	//go:generate go run github.com/Raj63/go-sdk/cmd/configdoc -type AppConfig -markdown ../../docs/config.md -env ../../.env.example
+//
+// The config package is loaded by a program generated next to it, so it can't be a main package.
+// The field doc comments are read from the packages of the main module and of the SDK.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+const sdkModule = "github.com/Raj63/go-sdk"
+
+var program = template.Must(template.New("main").Parse(`package main
+
+import (
+	"log"
+	"os"
+
+	sdkconfig "github.com/Raj63/go-sdk/config"
+	target "{{.Package}}"
+)
+
+func main() {
+	comments, err := sdkconfig.ParseFieldComments({{range .Dirs}}
+		{{printf "%q" .}},{{end}}
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	docs := sdkconfig.Docs(&target.{{.Type}}{}, comments)
+{{if .Markdown}}
+	if err := os.WriteFile({{printf "%q" .Markdown}}, []byte(docs.Markdown()), 0644); err != nil {
+		log.Fatal(err)
+	}
+{{end}}{{if .Env}}
+	if err := os.WriteFile({{printf "%q" .Env}}, []byte(docs.EnvExample()), 0644); err != nil {
+		log.Fatal(err)
+	}
+{{end}}}
+`))
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("configdoc: ")
+
+	pkg := flag.String("pkg", ".", "the import path or the directory of the config package")
+	typ := flag.String("type", "", "the name of the config struct type (required)")
+	markdown := flag.String("markdown", "", "the path of the Markdown reference to write")
+	env := flag.String("env", "", "the path of the .env.example file to write")
+	flag.Parse()
+
+	if *typ == "" || (*markdown == "" && *env == "") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*pkg, *typ, *markdown, *env); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(pkg, typ, markdown, env string) error {
+	importPath, err := goList("{{.ImportPath}}", pkg)
+	if err != nil {
+		return err
+	}
+
+	dirs, err := goList(`{{if and .Module (or .Module.Main (eq .Module.Path "`+sdkModule+`"))}}{{.Dir}}{{end}}`, "-deps", pkg)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range []*string{&markdown, &env} {
+		if *path != "" {
+			if *path, err = filepath.Abs(*path); err != nil {
+				return err
+			}
+		}
+	}
+
+	moduleDir, err := goList("{{.Module.Dir}}", pkg)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp(moduleDir, ".configdoc")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var src bytes.Buffer
+	if err := program.Execute(&src, map[string]interface{}{
+		"Package":  importPath,
+		"Type":     typ,
+		"Dirs":     strings.FieldsFunc(dirs, func(r rune) bool { return r == '\n' }),
+		"Markdown": markdown,
+		"Env":      env,
+	}); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), src.Bytes(), 0600); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("go", "run", ".")
+	cmd.Dir = tmpDir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("unable to generate the docs of %s.%s: %w", importPath, typ, err)
+	}
+
+	return nil
+}
+
+// goList runs `go list -f <format> <args>` and returns its trimmed output.
+func goList(format string, args ...string) (string, error) {
+	out, err := exec.Command("go", append([]string{"list", "-f", format}, args...)...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("go list: %s", bytes.TrimSpace(exitErr.Stderr))
+		}
+		return "", err
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
@@ -2,77 +2,136 @@ package config
 
 import (
 	"embed"
-	"errors"
+	"fmt"
+	"io/fs"
 	"net/http"
 	"os"
-	"reflect"
 	"strconv"
 	"strings"
 
 	"github.com/caarlos0/env/v6"
-	"go.mozilla.org/sops/v3/decrypt"
 )
 
-// ParseAppConfig parses the environment file variables into the interface.
+// ParseAppConfig parses the environment file variables into the interface and validates the
+// result, see Validate. The values that are secret references, e.g. "file:///run/secrets/x",
+// "env://OTHER_VAR" or "<provider>://<name>/<key>", are resolved with the registered
+// SecretProvider first, see RegisterSecretProvider.
 func ParseAppConfig(c interface{}) error {
-	return parseEnv(c)
+	if err := parseEnv(c); err != nil {
+		return err
+	}
+
+	return Validate(c)
 }
 
-// LoadDotenv is a helper function to load the dotenv file into environment variables
+// LoadDotenv is a helper function to load the dotenv file into environment variables. The file is
+// decrypted with the SecretDecrypter selected by the `CONFIG_DECRYPTER*` environment variables
+// (see DecrypterConfig) or, when they are unset, with SOPS if the `resourcePaths["sops"]` file
+// exists and `APP_ENV` isn't development.
 func LoadDotenv(embedFS embed.FS, resourcePaths map[string]string) error {
-	configFilePath := resourcePaths["configs"] + "/" + os.Getenv("APP_ENV") + ".env"
-	envs, err := embedFS.ReadFile(configFilePath)
+	vars, err := readEnvDotenv(embedFS, resourcePaths, os.Getenv("APP_ENV"))
 	if err != nil {
 		return err
 	}
 
-	if os.Getenv("APP_ENV") != "development" {
-		f, err := embedFS.Open(resourcePaths["sops"])
-		if err == nil {
-			if err := os.Setenv("AWS_PROFILE", os.Getenv("APP_ENV")); err != nil {
+	for k, v := range vars {
+		if os.Getenv(k) == "" {
+			if err := os.Setenv(k, v); err != nil {
 				return err
 			}
-
-			encryptedEnvs := strings.Trim(string(envs), "\n")
-			encryptedEnvs = strings.Trim(encryptedEnvs, " ")
-			envs, err = decrypt.Data([]byte(encryptedEnvs), "dotenv")
-			if err != nil {
-				return errors.New("unable to decrypt '" + configFilePath + "' with the specified AWS KMS key, please ensure that your AWS credential is configured properly with non-expired session")
-			}
 		}
+	}
 
-		if f != nil {
-			defer f.Close()
-		}
+	return nil
+}
+
+// readEnvDotenv reads, decrypts and parses the `<resourcePaths["configs"]>/<appEnv>.env` file the
+// same way as LoadDotenv.
+func readEnvDotenv(fsys fs.FS, resourcePaths map[string]string, appEnv string) (map[string]string, error) {
+	configFilePath := resourcePaths["configs"] + "/" + appEnv + ".env"
+	decrypter, err := resolveDecrypter(nil, fsys, resourcePaths["sops"], appEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	return readDotenvFile(fsys, configFilePath, decrypter)
+}
+
+// readDotenvFile reads, decrypts and parses the dotenv file.
+func readDotenvFile(fsys fs.FS, configFilePath string, decrypter SecretDecrypter) (map[string]string, error) {
+	envs, err := readFile(fsys, configFilePath, decrypter, "dotenv")
+	if err != nil {
+		return nil, err
 	}
 
 	if len(envs) == 0 {
-		return nil
+		return map[string]string{}, nil
+	}
+
+	vars, err := ParseDotenv(envs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse '%s': %w", configFilePath, err)
 	}
 
-	lines := strings.Split(string(envs), "\n")
-	for _, line := range lines {
-		if line != "" {
-			splits := strings.SplitN(line, "=", 2)
-			if os.Getenv(splits[0]) == "" {
-				if err := os.Setenv(splits[0], splits[1]); err != nil {
-					return err
-				}
+	if err := decryptValues(vars, decrypter); err != nil {
+		return nil, fmt.Errorf("unable to decrypt '%s': %w", configFilePath, err)
+	}
+
+	return vars, nil
+}
+
+// readFile reads the config file and decrypts it with the decrypter, if any, using the SOPS format.
+func readFile(fsys fs.FS, configFilePath string, decrypter SecretDecrypter, format string) ([]byte, error) {
+	data, err := fs.ReadFile(fsys, configFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if decrypter == nil {
+		return data, nil
+	}
+
+	data, err = decrypter.DecryptFile(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt '%s': %w", configFilePath, err)
+	}
+
+	return data, nil
+}
+
+func parseEnv(c interface{}, opts ...env.Options) error {
+	environment := map[string]string{}
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			environment[k] = v
+		}
+	}
+	for _, opt := range opts {
+		if opt.Environment != nil {
+			environment = make(map[string]string, len(opt.Environment))
+			for k, v := range opt.Environment {
+				environment[k] = v
 			}
 		}
 	}
 
-	return nil
-}
+	fields := structFields(c)
+	keys := make([]string, 0, len(fields))
+	for _, f := range fields {
+		keys = append(keys, f.Key)
+	}
+	if err := resolveSecretRefs(environment, keys); err != nil {
+		return err
+	}
+	if len(opts) == 0 {
+		opts = []env.Options{{}}
+	}
+	opts = append([]env.Options(nil), opts...)
+	for i := range opts {
+		opts[i].Environment = environment
+	}
 
-func parseEnv(c interface{}) error {
-	if err := env.ParseWithFuncs(c, map[reflect.Type]env.ParserFunc{
-		reflect.TypeOf([]byte{}):            parseByteArray,
-		reflect.TypeOf([][]byte{}):          parseByte2DArray,
-		reflect.TypeOf(map[string]int{}):    parseMapStrInt,
-		reflect.TypeOf(map[string]string{}): parseMapStrStr,
-		reflect.TypeOf(http.SameSite(1)):    parseHTTPSameSite,
-	}); err != nil {
+	if err := env.ParseWithFuncs(c, envParsers(), opts...); err != nil {
 		return err
 	}
 
@@ -103,36 +162,11 @@ func parseHTTPSameSite(v string) (interface{}, error) {
 }
 
 func parseMapStrInt(v string) (interface{}, error) {
-	newMaps := map[string]int{}
-	maps := strings.Split(v, ",")
-	for _, m := range maps {
-		splits := strings.Split(m, ":")
-		if len(splits) != 2 {
-			continue
-		}
-
-		val, err := strconv.Atoi(splits[1])
-		if err != nil {
-			return nil, err
-		}
-
-		newMaps[splits[0]] = val
-	}
-
-	return newMaps, nil
+	return parseMap(v, func(s string) (int, error) {
+		return strconv.Atoi(unescape(s))
+	})
 }
 
 func parseMapStrStr(v string) (interface{}, error) {
-	newMaps := map[string]string{}
-	maps := strings.Split(v, ",")
-	for _, m := range maps {
-		splits := strings.Split(m, ":")
-		if len(splits) != 2 {
-			continue
-		}
-
-		newMaps[splits[0]] = splits[1]
-	}
-
-	return newMaps, nil
+	return ParseMap(v)
 }
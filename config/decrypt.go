@@ -0,0 +1,336 @@
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	pgparmor "github.com/ProtonMail/go-crypto/openpgp/armor"
+	"go.mozilla.org/sops/v3/decrypt"
+)
+
+const (
+	// DecrypterSops decrypts the config files with SOPS, whose keys are usually held in AWS KMS.
+	DecrypterSops = "sops"
+
+	// DecrypterAge decrypts the config files and values with the age identities of a key file.
+	DecrypterAge = "age"
+
+	// DecrypterPGP decrypts the config files and values with the OpenPGP private keys of a key file.
+	DecrypterPGP = "pgp"
+
+	// DecrypterNone disables the decryption, see NoopDecrypter.
+	DecrypterNone = "none"
+)
+
+// encryptedValue matches a value encrypted on its own, e.g. `DB_PASSWORD=ENC[<base64>]`. The
+// SOPS values, e.g. `ENC[AES256_GCM,data:...]`, are left to SOPS.
+var encryptedValue = regexp.MustCompile(`^ENC\[([A-Za-z0-9+/=\s]+)\]$`)
+
+// SecretDecrypter decrypts the encrypted config files and the encrypted config values.
+type SecretDecrypter interface {
+	// DecryptFile decrypts the whole content of a config file in the SOPS format ("dotenv",
+	// "yaml", "json" or "binary"). The data is returned as-is when it isn't encrypted for the
+	// decrypter.
+	DecryptFile(data []byte, format string) ([]byte, error)
+
+	// DecryptValue decrypts a single base64 encoded value, i.e. the content of `ENC[...]`.
+	DecryptValue(ciphertext string) (string, error)
+}
+
+// DecrypterConfig indicates which SecretDecrypter should decrypt the config.
+type DecrypterConfig struct {
+	// Type indicates the decrypter to use, one of DecrypterSops, DecrypterAge, DecrypterPGP or
+	// DecrypterNone. By default, it is DecrypterSops.
+	Type string `env:"CONFIG_DECRYPTER" envDefault:"sops" validate:"oneof=sops age pgp none"`
+
+	// KeyFile is the path of the age identities or the armored OpenPGP private keys.
+	KeyFile string `env:"CONFIG_DECRYPTER_KEY_FILE"`
+
+	// Passphrase unlocks the OpenPGP private keys, if they are protected.
+	Passphrase string `env:"CONFIG_DECRYPTER_PASSPHRASE" secret:"true"`
+
+	// AWSProfile is the AWS profile that SOPS uses to reach AWS KMS, if any.
+	AWSProfile string `env:"CONFIG_DECRYPTER_AWS_PROFILE"`
+}
+
+// NewSecretDecrypter initialises the SecretDecrypter selected by the config.
+func NewSecretDecrypter(c *DecrypterConfig) (SecretDecrypter, error) {
+	switch c.Type {
+	case "", DecrypterSops:
+		return &SopsDecrypter{AWSProfile: c.AWSProfile}, nil
+	case DecrypterAge:
+		d, err := NewAgeDecrypter(c.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		return d, nil
+	case DecrypterPGP:
+		d, err := NewPGPDecrypter(c.KeyFile, []byte(c.Passphrase))
+		if err != nil {
+			return nil, err
+		}
+		return d, nil
+	case DecrypterNone:
+		return NoopDecrypter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown config decrypter %q", c.Type)
+	}
+}
+
+// NoopDecrypter is the SecretDecrypter of DecrypterNone, it leaves the config files and values
+// as-is.
+type NoopDecrypter struct{}
+
+// DecryptFile implements SecretDecrypter.
+func (NoopDecrypter) DecryptFile(data []byte, format string) ([]byte, error) {
+	return data, nil
+}
+
+// DecryptValue implements SecretDecrypter, the value being kept encrypted, i.e. `ENC[...]`.
+func (NoopDecrypter) DecryptValue(ciphertext string) (string, error) {
+	return "ENC[" + ciphertext + "]", nil
+}
+
+// SopsDecrypter decrypts the config files with SOPS. SOPS encrypts each value of a file with a
+// data key stored in the file, so the values can only be decrypted with their whole file.
+type SopsDecrypter struct {
+	// AWSProfile is set as the `AWS_PROFILE` environment variable before decrypting, if any.
+	AWSProfile string
+}
+
+// DecryptFile implements SecretDecrypter.
+func (d *SopsDecrypter) DecryptFile(data []byte, format string) ([]byte, error) {
+	if d.AWSProfile != "" {
+		if err := os.Setenv("AWS_PROFILE", d.AWSProfile); err != nil {
+			return nil, err
+		}
+	}
+
+	encrypted := strings.Trim(string(data), "\n")
+	encrypted = strings.Trim(encrypted, " ")
+	plaintext, err := decrypt.Data([]byte(encrypted), format)
+	if err != nil {
+		return nil, fmt.Errorf("sops: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// DecryptValue implements SecretDecrypter.
+func (d *SopsDecrypter) DecryptValue(ciphertext string) (string, error) {
+	return "", errors.New("sops: values can only be decrypted with their whole file")
+}
+
+// AgeDecrypter decrypts the config files and values encrypted with age, either binary or armored.
+type AgeDecrypter struct {
+	identities []age.Identity
+}
+
+// NewAgeDecrypter initialises an AgeDecrypter with the identities of the key file, as generated
+// by `age-keygen`.
+func NewAgeDecrypter(keyFile string) (*AgeDecrypter, error) {
+	f, err := os.Open(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("age: %w", err)
+	}
+	defer f.Close()
+
+	identities, err := age.ParseIdentities(f)
+	if err != nil {
+		return nil, fmt.Errorf("age: unable to parse the identities in '%s': %w", keyFile, err)
+	}
+
+	return &AgeDecrypter{identities}, nil
+}
+
+// DecryptFile implements SecretDecrypter.
+func (d *AgeDecrypter) DecryptFile(data []byte, format string) ([]byte, error) {
+	trimmed := bytes.TrimSpace(data)
+	var r io.Reader
+	switch {
+	case bytes.HasPrefix(trimmed, []byte(armor.Header)):
+		r = armor.NewReader(bytes.NewReader(trimmed))
+	case bytes.HasPrefix(data, []byte("age-encryption.org/")):
+		r = bytes.NewReader(data)
+	default:
+		return data, nil
+	}
+
+	return d.decrypt(r)
+}
+
+// DecryptValue implements SecretDecrypter.
+func (d *AgeDecrypter) DecryptValue(ciphertext string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(ciphertext), ""))
+	if err != nil {
+		return "", fmt.Errorf("age: %w", err)
+	}
+
+	plaintext, err := d.decrypt(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+func (d *AgeDecrypter) decrypt(r io.Reader) ([]byte, error) {
+	pr, err := age.Decrypt(r, d.identities...)
+	if err != nil {
+		return nil, fmt.Errorf("age: %w", err)
+	}
+
+	plaintext, err := io.ReadAll(pr)
+	if err != nil {
+		return nil, fmt.Errorf("age: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// PGPDecrypter decrypts the armored config files and the base64 encoded values encrypted with
+// OpenPGP.
+type PGPDecrypter struct {
+	keyring openpgp.EntityList
+}
+
+// NewPGPDecrypter initialises a PGPDecrypter with the armored private keys of the key file, the
+// passphrase unlocks the keys that are protected.
+func NewPGPDecrypter(keyFile string, passphrase []byte) (*PGPDecrypter, error) {
+	f, err := os.Open(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("pgp: %w", err)
+	}
+	defer f.Close()
+
+	keyring, err := openpgp.ReadArmoredKeyRing(f)
+	if err != nil {
+		return nil, fmt.Errorf("pgp: unable to read the keys in '%s': %w", keyFile, err)
+	}
+
+	for _, entity := range keyring {
+		keys := []*openpgp.Subkey{{PrivateKey: entity.PrivateKey}}
+		for i := range entity.Subkeys {
+			keys = append(keys, &entity.Subkeys[i])
+		}
+
+		for _, key := range keys {
+			if key.PrivateKey == nil || !key.PrivateKey.Encrypted {
+				continue
+			}
+
+			if err := key.PrivateKey.Decrypt(passphrase); err != nil {
+				return nil, fmt.Errorf("pgp: unable to unlock the keys in '%s': %w", keyFile, err)
+			}
+		}
+	}
+
+	return &PGPDecrypter{keyring}, nil
+}
+
+// DecryptFile implements SecretDecrypter.
+func (d *PGPDecrypter) DecryptFile(data []byte, format string) ([]byte, error) {
+	trimmed := bytes.TrimSpace(data)
+	if !bytes.HasPrefix(trimmed, []byte("-----BEGIN PGP MESSAGE-----")) {
+		return data, nil
+	}
+
+	block, err := pgparmor.Decode(bytes.NewReader(trimmed))
+	if err != nil {
+		return nil, fmt.Errorf("pgp: %w", err)
+	}
+
+	return d.decrypt(block.Body)
+}
+
+// DecryptValue implements SecretDecrypter.
+func (d *PGPDecrypter) DecryptValue(ciphertext string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(ciphertext), ""))
+	if err != nil {
+		return "", fmt.Errorf("pgp: %w", err)
+	}
+
+	plaintext, err := d.decrypt(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+func (d *PGPDecrypter) decrypt(r io.Reader) ([]byte, error) {
+	md, err := openpgp.ReadMessage(r, d.keyring, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("pgp: %w", err)
+	}
+
+	plaintext, err := io.ReadAll(md.UnverifiedBody)
+	if err != nil {
+		return nil, fmt.Errorf("pgp: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// resolveDecrypter returns the decrypter for the config files. Unless a decrypter is given, it is
+// selected by the `CONFIG_DECRYPTER*` environment variables (see DecrypterConfig) and, when they
+// are unset, SOPS is used with `AWS_PROFILE=<appEnv>` as long as the SOPS config file exists and
+// the environment isn't development. A nil decrypter means no decryption.
+func resolveDecrypter(decrypter SecretDecrypter, fsys fs.FS, sopsFilePath, appEnv string) (SecretDecrypter, error) {
+	if decrypter != nil {
+		return decrypter, nil
+	}
+
+	if os.Getenv("CONFIG_DECRYPTER") != "" {
+		c := &DecrypterConfig{}
+		if err := parseEnv(c); err != nil {
+			return nil, err
+		}
+
+		return NewSecretDecrypter(c)
+	}
+
+	if appEnv == "development" {
+		return nil, nil
+	}
+
+	f, err := fsys.Open(sopsFilePath)
+	if err != nil {
+		return nil, nil
+	}
+	defer f.Close()
+
+	return &SopsDecrypter{AWSProfile: appEnv}, nil
+}
+
+// decryptValues decrypts the values that are encrypted on their own, i.e. `ENC[...]`.
+func decryptValues(vars map[string]string, decrypter SecretDecrypter) error {
+	if decrypter == nil {
+		return nil
+	}
+
+	for k, v := range vars {
+		matches := encryptedValue.FindStringSubmatch(v)
+		if matches == nil {
+			continue
+		}
+
+		plaintext, err := decrypter.DecryptValue(matches[1])
+		if err != nil {
+			return fmt.Errorf("unable to decrypt the value of '%s': %w", k, err)
+		}
+		vars[k] = plaintext
+	}
+
+	return nil
+}
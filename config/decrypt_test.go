@@ -0,0 +1,167 @@
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	pgparmor "github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+)
+
+type decryptTestConfig struct {
+	Name       string `env:"DECRYPT_TEST_NAME"`
+	DBPassword string `env:"DECRYPT_TEST_DB_PASSWORD"`
+}
+
+func Test_AgeDecrypter(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	assert.Nil(t, err)
+
+	keyFile := filepath.Join(t.TempDir(), "keys.txt")
+	assert.Nil(t, os.WriteFile(keyFile, []byte("# test key\n"+identity.String()+"\n"), 0600))
+
+	encrypt := func(plaintext string, armored bool) []byte {
+		var buf bytes.Buffer
+		var dst io.WriteCloser = nopWriteCloser{&buf}
+		if armored {
+			dst = armor.NewWriter(&buf)
+		}
+		w, err := age.Encrypt(dst, identity.Recipient())
+		assert.Nil(t, err)
+		_, err = io.WriteString(w, plaintext)
+		assert.Nil(t, err)
+		assert.Nil(t, w.Close())
+		assert.Nil(t, dst.Close())
+		return buf.Bytes()
+	}
+
+	decrypter, err := NewSecretDecrypter(&DecrypterConfig{Type: DecrypterAge, KeyFile: keyFile})
+	assert.Nil(t, err)
+
+	t.Run("should decrypt the whole file", func(t *testing.T) {
+		c := decryptTestConfig{}
+		_, err := NewLoader(&LoaderConfig{
+			Decrypter: decrypter,
+			Env:       "production",
+			FS: fstest.MapFS{
+				"configs/production.env": {Data: encrypt("DECRYPT_TEST_NAME=app\nDECRYPT_TEST_DB_PASSWORD=s3cret\n", true)},
+			},
+		}).Load(&c)
+
+		assert.Nil(t, err)
+		assert.Equal(t, decryptTestConfig{Name: "app", DBPassword: "s3cret"}, c)
+	})
+
+	t.Run("should decrypt the ENC values", func(t *testing.T) {
+		value := base64.StdEncoding.EncodeToString(encrypt("s3cret", false))
+		c := decryptTestConfig{}
+		_, err := NewLoader(&LoaderConfig{
+			Decrypter: decrypter,
+			Env:       "production",
+			FS: fstest.MapFS{
+				"configs/production.yaml": {Data: []byte("decrypt_test_name: app\ndecrypt_test_db_password: ENC[" + value + "]\n")},
+			},
+		}).Load(&c)
+
+		assert.Nil(t, err)
+		assert.Equal(t, decryptTestConfig{Name: "app", DBPassword: "s3cret"}, c)
+	})
+
+	t.Run("should fail when the value is encrypted for another identity", func(t *testing.T) {
+		other, err := age.GenerateX25519Identity()
+		assert.Nil(t, err)
+		var buf bytes.Buffer
+		w, err := age.Encrypt(&buf, other.Recipient())
+		assert.Nil(t, err)
+		_, _ = io.WriteString(w, "s3cret")
+		assert.Nil(t, w.Close())
+
+		_, err = NewLoader(&LoaderConfig{
+			Decrypter: decrypter,
+			Env:       "production",
+			FS: fstest.MapFS{
+				"configs/production.env": {Data: []byte("DECRYPT_TEST_DB_PASSWORD=ENC[" + base64.StdEncoding.EncodeToString(buf.Bytes()) + "]\n")},
+			},
+		}).Load(&decryptTestConfig{})
+
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "unable to decrypt the value of 'DECRYPT_TEST_DB_PASSWORD'")
+	})
+}
+
+func Test_PGPDecrypter(t *testing.T) {
+	entity, err := openpgp.NewEntity("config", "test", "config@example.com", nil)
+	assert.Nil(t, err)
+
+	var key bytes.Buffer
+	w, err := pgparmor.Encode(&key, openpgp.PrivateKeyType, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, entity.SerializePrivate(w, nil))
+	assert.Nil(t, w.Close())
+
+	keyFile := filepath.Join(t.TempDir(), "private.asc")
+	assert.Nil(t, os.WriteFile(keyFile, key.Bytes(), 0600))
+
+	encrypt := func(plaintext string) []byte {
+		var buf bytes.Buffer
+		w, err := openpgp.Encrypt(&buf, []*openpgp.Entity{entity}, nil, nil, nil)
+		assert.Nil(t, err)
+		_, err = io.WriteString(w, plaintext)
+		assert.Nil(t, err)
+		assert.Nil(t, w.Close())
+		return buf.Bytes()
+	}
+
+	decrypter, err := NewSecretDecrypter(&DecrypterConfig{Type: DecrypterPGP, KeyFile: keyFile})
+	assert.Nil(t, err)
+
+	var armored bytes.Buffer
+	aw, err := pgparmor.Encode(&armored, "PGP MESSAGE", nil)
+	assert.Nil(t, err)
+	_, err = aw.Write(encrypt("DECRYPT_TEST_NAME=app\n"))
+	assert.Nil(t, err)
+	assert.Nil(t, aw.Close())
+
+	plaintext, err := decrypter.DecryptFile(armored.Bytes(), "dotenv")
+	assert.Nil(t, err)
+	assert.Equal(t, "DECRYPT_TEST_NAME=app\n", string(plaintext))
+
+	value, err := decrypter.DecryptValue(base64.StdEncoding.EncodeToString(encrypt("s3cret")))
+	assert.Nil(t, err)
+	assert.Equal(t, "s3cret", value)
+
+	plaintext, err = decrypter.DecryptFile([]byte("NOT_ENCRYPTED=1\n"), "dotenv")
+	assert.Nil(t, err)
+	assert.Equal(t, "NOT_ENCRYPTED=1\n", string(plaintext))
+}
+
+func Test_NoopDecrypter(t *testing.T) {
+	decrypter, err := NewSecretDecrypter(&DecrypterConfig{Type: DecrypterNone})
+	assert.Nil(t, err)
+
+	c := decryptTestConfig{}
+	_, err = NewLoader(&LoaderConfig{
+		Decrypter: decrypter,
+		Env:       "production",
+		FS: fstest.MapFS{
+			"configs/production.env": {Data: []byte("DECRYPT_TEST_NAME=app\nDECRYPT_TEST_DB_PASSWORD=ENC[czNjcmV0]\n")},
+		},
+	}).Load(&c)
+
+	assert.Nil(t, err)
+	assert.Equal(t, decryptTestConfig{Name: "app", DBPassword: "ENC[czNjcmV0]"}, c)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// DiffStatus describes how a key differs between two environments.
+type DiffStatus string
+
+const (
+	// DiffMissingLeft indicates that the key is only set in the right environment.
+	DiffMissingLeft DiffStatus = "missing_left"
+
+	// DiffMissingRight indicates that the key is only set in the left environment.
+	DiffMissingRight DiffStatus = "missing_right"
+
+	// DiffEmptyLeft indicates that the key is empty in the left environment only.
+	DiffEmptyLeft DiffStatus = "empty_left"
+
+	// DiffEmptyRight indicates that the key is empty in the right environment only.
+	DiffEmptyRight DiffStatus = "empty_right"
+
+	// DiffChanged indicates that the key has different values in the environments.
+	DiffChanged DiffStatus = "changed"
+)
+
+// secretKey matches the keys whose values are masked in the diff by default.
+var secretKey = regexp.MustCompile(`(?i)(PASSWORD|PASSWD|PASSPHRASE|SECRET|TOKEN|PRIVATE|CREDENTIAL|API_?KEY|_KEY$)`)
+
+// DiffEntry describes a key that differs between two environments.
+type DiffEntry struct {
+	// Key is the environment variable name.
+	Key string `json:"key"`
+
+	// Status describes how the key differs.
+	Status DiffStatus `json:"status"`
+
+	// Left is the value in the left environment with the secrets masked.
+	Left string `json:"left"`
+
+	// Right is the value in the right environment with the secrets masked.
+	Right string `json:"right"`
+}
+
+// DiffEntries are the differences between two environments, sorted by key.
+type DiffEntries []DiffEntry
+
+// DiffEnvs loads the dotenv files of the left and the right environments the same way as
+// LoadDotenv, i.e. `<resourcePaths["configs"]>/<env>.env` decrypted with the SecretDecrypter
+// resolved for the environment, and returns their differences. The values of the keys that look
+// like secrets, e.g. "DB_PASSWORD", and of the secretKeys are masked, and so are the passwords of
+// the DSNs.
+func DiffEnvs(fsys fs.FS, resourcePaths map[string]string, left, right string, secretKeys ...string) (DiffEntries, error) {
+	leftVars, err := readEnvDotenv(fsys, resourcePaths, left)
+	if err != nil {
+		return nil, err
+	}
+
+	rightVars, err := readEnvDotenv(fsys, resourcePaths, right)
+	if err != nil {
+		return nil, err
+	}
+
+	return DiffVars(leftVars, rightVars, secretKeys...), nil
+}
+
+// DiffVars returns the differences between the left and the right environment variables, with
+// the secrets masked, see DiffEnvs.
+func DiffVars(left, right map[string]string, secretKeys ...string) DiffEntries {
+	secrets := map[string]bool{}
+	for _, k := range secretKeys {
+		secrets[k] = true
+	}
+
+	keys := make([]string, 0, len(left)+len(right))
+	for k := range left {
+		keys = append(keys, k)
+	}
+	for k := range right {
+		if _, ok := left[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	entries := DiffEntries{}
+	for _, k := range keys {
+		l, inLeft := left[k]
+		r, inRight := right[k]
+
+		var status DiffStatus
+		switch {
+		case !inLeft:
+			status = DiffMissingLeft
+		case !inRight:
+			status = DiffMissingRight
+		case l == r:
+			continue
+		case l == "":
+			status = DiffEmptyLeft
+		case r == "":
+			status = DiffEmptyRight
+		default:
+			status = DiffChanged
+		}
+
+		mask := func(v string) string {
+			if v != "" && (secrets[k] || secretKey.MatchString(k)) {
+				return Mask
+			}
+			return MaskDSN(v)
+		}
+
+		entries = append(entries, DiffEntry{Key: k, Status: status, Left: mask(l), Right: mask(r)})
+	}
+
+	return entries
+}
+
+// Table renders the differences as a text table, the columns of the values are named after the
+// environments.
+func (e DiffEntries) Table(left, right string) string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "KEY\tSTATUS\t%s\t%s\n", strings.ToUpper(left), strings.ToUpper(right))
+	for _, entry := range e {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", entry.Key, entry.Status.describe(left, right), entry.Left, entry.Right)
+	}
+	w.Flush()
+
+	return sb.String()
+}
+
+func (s DiffStatus) describe(left, right string) string {
+	switch s {
+	case DiffMissingLeft:
+		return "missing in " + left
+	case DiffMissingRight:
+		return "missing in " + right
+	case DiffEmptyLeft:
+		return "empty in " + left
+	case DiffEmptyRight:
+		return "empty in " + right
+	default:
+		return string(s)
+	}
+}
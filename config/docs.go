@@ -0,0 +1,230 @@
+package config
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"strings"
+)
+
+// DocEntry describes a config field for the generated documentation.
+type DocEntry struct {
+	// Field is the dotted Go path of the field, e.g. "DB.URI".
+	Field string
+
+	// Key is the environment variable name of the field, e.g. "DB_URI".
+	Key string
+
+	// Type is the Go type of the field, e.g. "time.Duration".
+	Type string
+
+	// Default is the value of the `envDefault` tag, if any.
+	Default string
+
+	// Required indicates whether the value must be set, i.e. the field has the `required` or
+	// `notEmpty` env tag option or the `required` validation rule.
+	Required bool
+
+	// Validation is the value of the `validate` tag, if any.
+	Validation string
+
+	// Secret indicates whether the field has the `secret:"true"` tag, or lives under a struct that
+	// has it.
+	Secret bool
+
+	// Description is the doc comment of the field, if any.
+	Description string
+}
+
+// DocEntries is the config reference, as returned by Docs.
+type DocEntries []DocEntry
+
+// FieldComments holds the doc comments of the struct fields keyed by `<package>.<Type>.<Field>`,
+// e.g. "config.DecrypterConfig.KeyFile", as returned by ParseFieldComments.
+type FieldComments map[string]string
+
+// Docs returns the reference of every field of the config struct (or pointer to struct) that is
+// loaded from an environment variable, in the struct order. The descriptions are looked up in the
+// comments, which may be nil.
+func Docs(c interface{}, comments FieldComments) DocEntries {
+	entries := DocEntries{}
+	for _, f := range structFields(c) {
+		tag := f.StructField.Tag
+		_, opts := parseKeyForOption(tag.Get("env"))
+		required := false
+		for _, opt := range opts {
+			if opt == "required" || opt == "notEmpty" {
+				required = true
+			}
+		}
+		for _, rule := range strings.Split(tag.Get("validate"), ",") {
+			if strings.TrimSpace(rule) == "required" {
+				required = true
+			}
+		}
+
+		entries = append(entries, DocEntry{
+			Field:       f.Path,
+			Key:         f.Key,
+			Type:        f.StructField.Type.String(),
+			Default:     tag.Get("envDefault"),
+			Required:    required,
+			Validation:  tag.Get("validate"),
+			Secret:      f.Secret,
+			Description: comments[f.Struct.String()+"."+f.StructField.Name],
+		})
+	}
+
+	return entries
+}
+
+// ParseFieldComments parses the Go files, except the tests, of the package directories and returns
+// the doc comments of their struct fields.
+func ParseFieldComments(dirs ...string) (FieldComments, error) {
+	comments := FieldComments{}
+	for _, dir := range dirs {
+		pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(info fs.FileInfo) bool {
+			return !strings.HasSuffix(info.Name(), "_test.go")
+		}, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse '%s': %w", dir, err)
+		}
+
+		for _, pkg := range pkgs {
+			for _, file := range pkg.Files {
+				ast.Inspect(file, func(n ast.Node) bool {
+					spec, ok := n.(*ast.TypeSpec)
+					if !ok {
+						return true
+					}
+
+					st, ok := spec.Type.(*ast.StructType)
+					if !ok {
+						return true
+					}
+
+					for _, f := range st.Fields.List {
+						doc := f.Doc.Text()
+						if doc == "" {
+							doc = f.Comment.Text()
+						}
+
+						for _, name := range f.Names {
+							if doc != "" {
+								comments[pkg.Name+"."+spec.Name.Name+"."+name.Name] = strings.TrimSpace(doc)
+							}
+						}
+					}
+
+					return true
+				})
+			}
+		}
+	}
+
+	return comments, nil
+}
+
+// Markdown renders the config reference as a Markdown table, the defaults of the secrets being
+// masked as in the config dump, see Mask.
+func (e DocEntries) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString("| Variable | Type | Default | Required | Validation | Description |\n")
+	sb.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+	for _, entry := range e {
+		required := "no"
+		if entry.Required {
+			required = "yes"
+		}
+
+		description := strings.Join(strings.Fields(entry.Description), " ")
+		defaultValue := entry.Default
+		if entry.Secret {
+			description = strings.TrimSpace("**Secret.** " + description)
+			if defaultValue != "" {
+				defaultValue = Mask
+			}
+		}
+
+		fmt.Fprintf(&sb, "| `%s` | `%s` | %s | %s | %s | %s |\n",
+			entry.Key,
+			entry.Type,
+			markdownCode(defaultValue),
+			required,
+			markdownCode(entry.Validation),
+			markdownEscape(description),
+		)
+	}
+
+	return sb.String()
+}
+
+// EnvExample renders the config reference as a commented `.env.example` file. The required
+// variables are left empty and the optional ones are commented out with their default value, the
+// secrets are always left empty.
+func (e DocEntries) EnvExample() string {
+	var sb strings.Builder
+	for i, entry := range e {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+
+		for _, line := range strings.Split(entry.Description, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				sb.WriteString("# " + line + "\n")
+			}
+		}
+
+		notes := []string{"Type: " + entry.Type + "."}
+		if entry.Required {
+			notes = append(notes, "Required.")
+		}
+		if entry.Validation != "" {
+			notes = append(notes, "Validation: "+entry.Validation+".")
+		}
+		if entry.Secret {
+			notes = append(notes, "Secret.")
+		}
+		sb.WriteString("# " + strings.Join(notes, " ") + "\n")
+
+		value := dotenvQuote(entry.Default)
+		if entry.Secret {
+			value = ""
+		}
+
+		if entry.Required {
+			sb.WriteString(entry.Key + "=" + value + "\n")
+		} else {
+			sb.WriteString("# " + entry.Key + "=" + value + "\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// dotenvQuote quotes the value for ParseDotenv, if needed.
+func dotenvQuote(v string) string {
+	if !strings.ContainsAny(v, " \t#\"'\\$\n") {
+		return v
+	}
+
+	if !strings.ContainsAny(v, "'\n") {
+		return "'" + v + "'"
+	}
+
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`).Replace(v) + `"`
+}
+
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return "`" + strings.ReplaceAll(s, "|", `\|`) + "`"
+}
+
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
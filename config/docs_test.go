@@ -0,0 +1,57 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type docsTestConfig struct {
+	// Name is the application name.
+	Name string `env:"DOCS_TEST_NAME,required"`
+
+	// Timeout bounds every request.
+	Timeout time.Duration `env:"DOCS_TEST_TIMEOUT" envDefault:"5s" validate:"duration>0"`
+
+	DB docsTestDBConfig `envPrefix:"DOCS_TEST_DB_" secret:"true"`
+}
+
+type docsTestDBConfig struct {
+	// URI is the database DSN.
+	URI string `env:"URI" envDefault:"postgres://localhost/app" validate:"required,url"`
+}
+
+func Test_Docs(t *testing.T) {
+	comments, err := ParseFieldComments(".")
+	assert.Nil(t, err)
+	assert.Equal(t, "KeyFile is the path of the age identities or the armored OpenPGP private keys.", comments["config.DecrypterConfig.KeyFile"])
+
+	comments = FieldComments{
+		"config.docsTestConfig.Name":    "Name is the application name.",
+		"config.docsTestConfig.Timeout": "Timeout bounds every request.",
+		"config.docsTestDBConfig.URI":   "URI is the database DSN.",
+	}
+	docs := Docs(&docsTestConfig{}, comments)
+	assert.Equal(t, DocEntries{
+		{Field: "Name", Key: "DOCS_TEST_NAME", Type: "string", Required: true, Description: "Name is the application name."},
+		{Field: "Timeout", Key: "DOCS_TEST_TIMEOUT", Type: "time.Duration", Default: "5s", Validation: "duration>0", Description: "Timeout bounds every request."},
+		{Field: "DB.URI", Key: "DOCS_TEST_DB_URI", Type: "string", Default: "postgres://localhost/app", Required: true, Validation: "required,url", Secret: true, Description: "URI is the database DSN."},
+	}, docs)
+
+	assert.Equal(t, "| Variable | Type | Default | Required | Validation | Description |\n"+
+		"| --- | --- | --- | --- | --- | --- |\n"+
+		"| `DOCS_TEST_NAME` | `string` |  | yes |  | Name is the application name. |\n"+
+		"| `DOCS_TEST_TIMEOUT` | `time.Duration` | `5s` | no | `duration>0` | Timeout bounds every request. |\n"+
+		"| `DOCS_TEST_DB_URI` | `string` | `******` | yes | `required,url` | **Secret.** URI is the database DSN. |\n",
+		docs.Markdown())
+
+	assert.Equal(t, "# Name is the application name.\n# Type: string. Required.\nDOCS_TEST_NAME=\n\n"+
+		"# Timeout bounds every request.\n# Type: time.Duration. Validation: duration>0.\n# DOCS_TEST_TIMEOUT=5s\n\n"+
+		"# URI is the database DSN.\n# Type: string. Required. Validation: required,url. Secret.\nDOCS_TEST_DB_URI=\n",
+		docs.EnvExample())
+
+	vars, err := ParseDotenv([]byte(docs.EnvExample()))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"DOCS_TEST_NAME": "", "DOCS_TEST_DB_URI": ""}, vars)
+}
@@ -0,0 +1,314 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ParseDotenv parses the dotenv formatted content into a map of environment variables.
+//
+// The supported syntax is:
+//   - blank lines and lines starting with `#` are ignored
+//   - an optional `export ` prefix before the key
+//   - unquoted values, where a ` #` starts an inline comment and a trailing `\` continues the
+//     value on the next line
+//   - single-quoted values, which are taken literally and may span multiple lines
+//   - double-quoted values, which may span multiple lines and understand the `\n`, `\r`, `\t`,
+//     `\"`, `\\` and `\$` escapes
+//   - `${VAR}` interpolation in unquoted and double-quoted values, which is resolved against the
+//     keys defined earlier in the content first and the process environment variables second
+//
+// Any syntax error is reported with the line number where it occurred.
+func ParseDotenv(data []byte) (map[string]string, error) {
+	p := &dotenvParser{
+		src:  strings.ReplaceAll(string(data), "\r\n", "\n"),
+		line: 1,
+		vars: map[string]string{},
+	}
+
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+
+	return p.vars, nil
+}
+
+type dotenvParser struct {
+	src  string
+	pos  int
+	line int
+	vars map[string]string
+}
+
+func (p *dotenvParser) parse() error {
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return nil
+		}
+
+		if p.peek() == '#' {
+			p.skipLine()
+			continue
+		}
+
+		if err := p.parseEntry(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *dotenvParser) parseEntry() error {
+	line := p.line
+	key := p.readKey()
+	if key == "export" && !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.skipSpaces()
+		key = p.readKey()
+	}
+
+	if key == "" {
+		return p.errorf(line, "invalid key, keys may only contain letters, digits, '_', '.' and '-'")
+	}
+
+	p.skipSpaces()
+	if p.eof() || p.peek() != '=' {
+		return p.errorf(line, "missing '=' after key %q", key)
+	}
+	p.pos++
+	p.skipSpaces()
+
+	var (
+		value string
+		err   error
+	)
+
+	switch {
+	case !p.eof() && p.peek() == '\'':
+		value, err = p.readSingleQuoted(line)
+	case !p.eof() && p.peek() == '"':
+		value, err = p.readDoubleQuoted(line)
+	default:
+		value, err = p.readUnquoted(line)
+	}
+	if err != nil {
+		return err
+	}
+
+	p.vars[key] = value
+
+	return nil
+}
+
+func (p *dotenvParser) readKey() string {
+	start := p.pos
+	for !p.eof() && isKeyChar(p.peek()) {
+		p.pos++
+	}
+
+	return p.src[start:p.pos]
+}
+
+func (p *dotenvParser) readSingleQuoted(line int) (string, error) {
+	p.pos++
+	end := strings.IndexByte(p.src[p.pos:], '\'')
+	if end < 0 {
+		return "", p.errorf(line, "unterminated single-quoted value")
+	}
+
+	value := p.src[p.pos : p.pos+end]
+	p.line += strings.Count(value, "\n")
+	p.pos += end + 1
+
+	return value, p.expectLineEnd(line)
+}
+
+func (p *dotenvParser) readDoubleQuoted(line int) (string, error) {
+	p.pos++
+	start := p.pos
+	for {
+		if p.eof() {
+			return "", p.errorf(line, "unterminated double-quoted value")
+		}
+
+		c := p.peek()
+		if c == '\\' && p.pos+1 < len(p.src) {
+			if p.src[p.pos+1] == '\n' {
+				p.line++
+			}
+			p.pos += 2
+			continue
+		}
+
+		if c == '"' {
+			break
+		}
+
+		if c == '\n' {
+			p.line++
+		}
+		p.pos++
+	}
+
+	raw := p.src[start:p.pos]
+	p.pos++
+
+	value, err := p.expand(raw, true, line)
+	if err != nil {
+		return "", err
+	}
+
+	return value, p.expectLineEnd(line)
+}
+
+func (p *dotenvParser) readUnquoted(line int) (string, error) {
+	var sb strings.Builder
+	for !p.eof() {
+		c := p.peek()
+		if c == '\n' {
+			break
+		}
+
+		// a trailing backslash escapes the newline and continues the value on the next line
+		if c == '\\' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '\n' {
+			p.pos += 2
+			p.line++
+			continue
+		}
+
+		// an inline comment must be separated from the value by a whitespace
+		if c == '#' && isSpace(p.src[p.pos-1]) {
+			p.skipLine()
+			break
+		}
+
+		sb.WriteByte(c)
+		p.pos++
+	}
+
+	return p.expand(strings.TrimRight(sb.String(), " \t"), false, line)
+}
+
+// expand processes the escape sequences and the `${VAR}` interpolations in a value.
+func (p *dotenvParser) expand(raw string, doubleQuoted bool, line int) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		if c == '\\' && i+1 < len(raw) {
+			next := raw[i+1]
+			switch {
+			case next == '$':
+				sb.WriteByte('$')
+				i++
+				continue
+			case !doubleQuoted:
+			case next == 'n':
+				sb.WriteByte('\n')
+				i++
+				continue
+			case next == 'r':
+				sb.WriteByte('\r')
+				i++
+				continue
+			case next == 't':
+				sb.WriteByte('\t')
+				i++
+				continue
+			case next == '"', next == '\\':
+				sb.WriteByte(next)
+				i++
+				continue
+			case next == '\n':
+				i++
+				continue
+			}
+		}
+
+		if c == '$' && i+1 < len(raw) && raw[i+1] == '{' {
+			end := strings.IndexByte(raw[i+2:], '}')
+			if end < 0 {
+				return "", p.errorf(line, "unterminated variable reference in %q", raw)
+			}
+
+			name := raw[i+2 : i+2+end]
+			if name == "" || strings.IndexFunc(name, func(r rune) bool { return r > 127 || !isKeyChar(byte(r)) }) >= 0 {
+				return "", p.errorf(line, "invalid variable reference '${%s}'", name)
+			}
+
+			sb.WriteString(p.lookup(name))
+			i += end + 2
+			continue
+		}
+
+		sb.WriteByte(c)
+	}
+
+	return sb.String(), nil
+}
+
+func (p *dotenvParser) lookup(name string) string {
+	if v, ok := p.vars[name]; ok {
+		return v
+	}
+
+	return os.Getenv(name)
+}
+
+func (p *dotenvParser) expectLineEnd(line int) error {
+	p.skipSpaces()
+	if p.eof() || p.peek() == '\n' {
+		return nil
+	}
+
+	if p.peek() == '#' {
+		p.skipLine()
+		return nil
+	}
+
+	return p.errorf(line, "unexpected characters after the quoted value")
+}
+
+func (p *dotenvParser) skipBlank() {
+	for !p.eof() {
+		c := p.peek()
+		if c == '\n' {
+			p.line++
+		} else if !isSpace(c) {
+			return
+		}
+		p.pos++
+	}
+}
+
+func (p *dotenvParser) skipSpaces() {
+	for !p.eof() && isSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func (p *dotenvParser) skipLine() {
+	for !p.eof() && p.peek() != '\n' {
+		p.pos++
+	}
+}
+
+func (p *dotenvParser) peek() byte {
+	return p.src[p.pos]
+}
+
+func (p *dotenvParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *dotenvParser) errorf(line int, format string, args ...interface{}) error {
+	return fmt.Errorf("dotenv: line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func isKeyChar(c byte) bool {
+	return c == '_' || c == '.' || c == '-' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v'
+}
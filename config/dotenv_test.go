@@ -0,0 +1,141 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseDotenv(t *testing.T) {
+	os.Setenv("DOTENV_TEST_HOST", "localhost")
+	defer os.Unsetenv("DOTENV_TEST_HOST")
+
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:  "when the content is empty",
+			input: "",
+			want:  map[string]string{},
+		},
+		{
+			name:  "when the content has comments and blank lines",
+			input: "# comment\n\n  # indented comment\nFOO=bar\n",
+			want:  map[string]string{"FOO": "bar"},
+		},
+		{
+			name:  "when the values are unquoted",
+			input: "FOO = bar baz  \nURI=postgres://u:p@host:5432/db?sslmode=disable\nEMPTY=\nHASH=#notacomment\nINLINE=value # comment",
+			want: map[string]string{
+				"FOO":    "bar baz",
+				"URI":    "postgres://u:p@host:5432/db?sslmode=disable",
+				"EMPTY":  "",
+				"HASH":   "#notacomment",
+				"INLINE": "value",
+			},
+		},
+		{
+			name:  "when the keys have the export prefix",
+			input: "export FOO=bar\nexport\tBAR=baz\nexport=1",
+			want:  map[string]string{"FOO": "bar", "BAR": "baz", "export": "1"},
+		},
+		{
+			name:  "when the values are single-quoted",
+			input: "FOO='bar # not a comment ${BAZ} \\n'\nMULTI='line1\nline2' # comment",
+			want:  map[string]string{"FOO": "bar # not a comment ${BAZ} \\n", "MULTI": "line1\nline2"},
+		},
+		{
+			name:  "when the values are double-quoted",
+			input: "FOO=\"bar\\nbaz\\t\\\"q\\\" \\\\ \\$HOME\"\nMULTI=\"line1\nline2\"",
+			want:  map[string]string{"FOO": "bar\nbaz\t\"q\" \\ $HOME", "MULTI": "line1\nline2"},
+		},
+		{
+			name:  "when the unquoted value has an escaped newline",
+			input: "FOO=bar \\\nbaz\nBAR=qux",
+			want:  map[string]string{"FOO": "bar baz", "BAR": "qux"},
+		},
+		{
+			name:  "when the values are interpolated",
+			input: "USER=admin\nURI=\"postgres://${USER}@${DOTENV_TEST_HOST}/db\"\nRAW='${USER}'\nUNSET=${DOTENV_TEST_UNSET}\nESCAPED=\\${USER}",
+			want: map[string]string{
+				"USER":    "admin",
+				"URI":     "postgres://admin@localhost/db",
+				"RAW":     "${USER}",
+				"UNSET":   "",
+				"ESCAPED": "${USER}",
+			},
+		},
+		{
+			name:  "when the content has windows line endings",
+			input: "FOO=bar\r\nBAR=baz\r\n",
+			want:  map[string]string{"FOO": "bar", "BAR": "baz"},
+		},
+		{
+			name:  "when the last value is empty at the end of the content",
+			input: "FOO=bar\nKEY=",
+			want:  map[string]string{"FOO": "bar", "KEY": ""},
+		},
+		{
+			name:  "when the last value is blank at the end of the content",
+			input: "KEY= ",
+			want:  map[string]string{"KEY": ""},
+		},
+		{
+			name:    "when the content ends with a bare export",
+			input:   "export",
+			wantErr: "dotenv: line 1: missing '=' after key \"export\"",
+		},
+		{
+			name:    "when the last line is a bare export",
+			input:   "A=1\nexport",
+			wantErr: "dotenv: line 2: missing '=' after key \"export\"",
+		},
+		{
+			name:    "when a line has no '='",
+			input:   "FOO=bar\n\nINVALID_LINE\n",
+			wantErr: "dotenv: line 3: missing '=' after key \"INVALID_LINE\"",
+		},
+		{
+			name:    "when a key is invalid",
+			input:   "FOO=bar\n=baz",
+			wantErr: "dotenv: line 2: invalid key",
+		},
+		{
+			name:    "when a double-quoted value is unterminated",
+			input:   "FOO=bar\nBAR=\"baz\nQUX=1",
+			wantErr: "dotenv: line 2: unterminated double-quoted value",
+		},
+		{
+			name:    "when a single-quoted value is unterminated",
+			input:   "FOO='bar",
+			wantErr: "dotenv: line 1: unterminated single-quoted value",
+		},
+		{
+			name:    "when a quoted value is followed by other characters",
+			input:   "FOO=\"bar\"baz",
+			wantErr: "dotenv: line 1: unexpected characters after the quoted value",
+		},
+		{
+			name:    "when a variable reference is unterminated",
+			input:   "FOO=bar\nBAR=${FOO",
+			wantErr: "dotenv: line 2: unterminated variable reference",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDotenv([]byte(tt.input))
+
+			if tt.wantErr != "" {
+				assert.NotNil(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.Nil(t, err)
+				assert.Equalf(t, tt.want, got, "ParseDotenv() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Mask replaces the secret values in the config dump.
+const Mask = "******"
+
+var (
+	// dsnKeyValuePassword matches the password in a key/value DSN, e.g. "host=db password=secret".
+	dsnKeyValuePassword = regexp.MustCompile(`(?i)\b(password|pwd)\s*=\s*('[^']*'|[^\s;]*)`)
+
+	// dsnUserPassword matches the password in a MySQL DSN, e.g. "user:secret@tcp(db:3306)/app".
+	dsnUserPassword = regexp.MustCompile(`^([^:@/\s]+):([^@\s]+)@`)
+)
+
+// DumpEntry describes the effective value of a config field.
+type DumpEntry struct {
+	// Field is the dotted Go path of the field, e.g. "DB.URI".
+	Field string `json:"field"`
+
+	// Key is the environment variable name of the field, if it has an `env` tag.
+	Key string `json:"key,omitempty"`
+
+	// Value is the field's value with the secrets masked.
+	Value string `json:"value"`
+}
+
+// DumpEntries is the effective config, as returned by Dump.
+type DumpEntries []DumpEntry
+
+// Dump returns the effective value of every exported field in the config struct (or pointer to
+// struct) with the secrets masked. A value is masked when its field has the `secret:"true"` tag,
+// and the password of any value that looks like a DSN, e.g. "postgres://user:secret@db/app", is
+// masked too.
+func Dump(c interface{}) DumpEntries {
+	keys := map[string]string{}
+	for _, f := range structFields(c) {
+		keys[f.Path] = f.Key
+	}
+
+	entries := DumpEntries{}
+	dumpValue(&entries, reflect.ValueOf(c), "", false, keys, map[dumpVisit]bool{})
+
+	return entries
+}
+
+// JSON renders the effective config as a JSON array.
+func (e DumpEntries) JSON() ([]byte, error) {
+	return json.MarshalIndent(e, "", "  ")
+}
+
+// Table renders the effective config as a text table.
+func (e DumpEntries) Table() string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "FIELD\tKEY\tVALUE")
+	for _, entry := range e {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", entry.Field, entry.Key, entry.Value)
+	}
+	w.Flush()
+
+	return sb.String()
+}
+
+// dumpVisit is a pointer that is being dumped, to skip the cycles, e.g. the app config being its
+// own MiddlewaresConfig.DebugConfig.
+type dumpVisit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+func dumpValue(entries *DumpEntries, v reflect.Value, path string, secret bool, keys map[string]string, visiting map[dumpVisit]bool) {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
+		if v.Kind() == reflect.Ptr {
+			visit := dumpVisit{v.Pointer(), v.Type()}
+			if visiting[visit] {
+				return
+			}
+			visiting[visit] = true
+			defer delete(visiting, visit)
+		}
+		v = v.Elem()
+	}
+
+	if v.IsValid() && v.Kind() == reflect.Struct && !isLeafStruct(v) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if sf.PkgPath != "" {
+				continue
+			}
+
+			fieldPath := sf.Name
+			if path != "" {
+				fieldPath = path + "." + sf.Name
+			}
+			dumpValue(entries, v.Field(i), fieldPath, secret || sf.Tag.Get("secret") == "true", keys, visiting)
+		}
+		return
+	}
+
+	if v.IsValid() && (v.Kind() == reflect.Func || v.Kind() == reflect.Chan || v.Kind() == reflect.UnsafePointer) {
+		return
+	}
+
+	value := formatValue(v)
+	if secret && value != "" {
+		value = Mask
+	} else {
+		value = MaskDSN(value)
+	}
+
+	*entries = append(*entries, DumpEntry{Field: path, Key: keys[path], Value: value})
+}
+
+func isLeafStruct(v reflect.Value) bool {
+	if _, ok := v.Interface().(time.Time); ok {
+		return true
+	}
+
+	_, ok := v.Interface().(fmt.Stringer)
+	return ok
+}
+
+func formatValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface || v.Kind() == reflect.Map || v.Kind() == reflect.Slice) && v.IsNil() {
+		return ""
+	}
+
+	switch val := v.Interface().(type) {
+	case []byte:
+		return string(val)
+	case fmt.Stringer:
+		return val.String()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, MaskDSN(formatValue(v.Index(i))))
+		}
+		return strings.Join(items, ",")
+	case reflect.Map:
+		items := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			items = append(items, fmt.Sprintf("%v:%s", iter.Key().Interface(), MaskDSN(formatValue(iter.Value()))))
+		}
+		sort.Strings(items)
+		return strings.Join(items, ",")
+	}
+
+	return fmt.Sprint(v.Interface())
+}
+
+// MaskDSN masks the password in a DSN such as "postgres://user:secret@db/app",
+// "user:secret@tcp(db:3306)/app" or "host=db password=secret". Any other value is returned as-is.
+func MaskDSN(value string) string {
+	if u, err := url.Parse(value); err == nil && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			// mask the raw password to keep the rest of the value untouched by re-encoding
+			start := strings.Index(value, "//") + 2
+			authority := value[start:]
+			if end := strings.IndexAny(authority, "/?#"); end >= 0 {
+				authority = authority[:end]
+			}
+			userinfo := authority[:strings.LastIndex(authority, "@")]
+			username, _, _ := strings.Cut(userinfo, ":")
+
+			return value[:start] + username + ":" + Mask + value[start+len(userinfo):]
+		}
+	}
+
+	if dsnUserPassword.MatchString(value) && strings.Contains(value, "(") {
+		return dsnUserPassword.ReplaceAllString(value, "${1}:"+Mask+"@")
+	}
+
+	return dsnKeyValuePassword.ReplaceAllString(value, "${1}="+Mask)
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// field describes a config struct field that is loaded from an environment variable.
+type field struct {
+	// Path is the dotted Go path of the field, e.g. "DB.URI".
+	Path string
+
+	// Key is the environment variable name of the field including any `envPrefix`, e.g. "DB_URI".
+	Key string
+
+	// StructField is the reflected struct field.
+	StructField reflect.StructField
+
+	// Value is the field's value, it is invalid when the field lives under a nil struct pointer.
+	Value reflect.Value
+
+	// Struct is the struct type that declares the field.
+	Struct reflect.Type
+
+	// Secret indicates whether the field, or any struct that it lives under, has the
+	// `secret:"true"` tag.
+	Secret bool
+}
+
+// structFields walks the struct (or the pointer to struct) the same way as `ParseAppConfig`
+// and returns every field that has an `env` tag.
+func structFields(c interface{}) []field {
+	v := reflect.ValueOf(c)
+	t := reflect.TypeOf(c)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		if v.IsValid() && !v.IsNil() {
+			v = v.Elem()
+		} else {
+			v = reflect.Value{}
+		}
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	return appendStructFields(nil, t, v, "", "", false)
+}
+
+func appendStructFields(fields []field, t reflect.Type, v reflect.Value, pathPrefix, keyPrefix string, secret bool) []field {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		var fv reflect.Value
+		if v.IsValid() {
+			fv = v.Field(i)
+		}
+
+		path := pathPrefix + sf.Name
+		secret := secret || sf.Tag.Get("secret") == "true"
+		if key, _ := parseKeyForOption(sf.Tag.Get("env")); key != "" {
+			fields = append(fields, field{
+				Path:        path,
+				Key:         keyPrefix + key,
+				StructField: sf,
+				Value:       fv,
+				Struct:      t,
+				Secret:      secret,
+			})
+			continue
+		}
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr && ft.Elem().Kind() == reflect.Struct {
+			ft = ft.Elem()
+			if fv.IsValid() && !fv.IsNil() {
+				fv = fv.Elem()
+			} else {
+				fv = reflect.Value{}
+			}
+		}
+
+		if ft.Kind() == reflect.Struct {
+			fields = appendStructFields(fields, ft, fv, path+".", keyPrefix+sf.Tag.Get("envPrefix"), secret)
+		}
+	}
+
+	return fields
+}
+
+// parseKeyForOption splits the `env` tag into the key and its options.
+func parseKeyForOption(tag string) (string, []string) {
+	opts := strings.Split(tag, ",")
+	return opts[0], opts[1:]
+}
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// FileExtensions are the config file extensions supported by the loaders, in the order that they
+// are looked up when a config file name has no extension.
+var FileExtensions = []string{".env", ".yaml", ".yml", ".toml", ".json"}
+
+// sopsFormats maps each config file extension to the SOPS format used to decrypt it. As SOPS has
+// no TOML support, TOML files are expected to be encrypted with `--input-type binary`.
+var sopsFormats = map[string]string{
+	".env":  "dotenv",
+	".yaml": "yaml",
+	".yml":  "yaml",
+	".toml": "binary",
+	".json": "json",
+}
+
+// ParseFile parses the dotenv, YAML, TOML or JSON content into a map of environment variables,
+// the format is picked from the file extension. The nested keys of YAML, TOML and JSON documents
+// are upper-cased and joined with `_` to match the `env` and `envPrefix` tags, e.g.
+//
+//	db:
+//	  max-conns: 16
+//
+// gives `DB_MAX_CONNS=16`. The lists are joined with `,` and, for the keys listed in mapKeys, the
+// nested maps are encoded as `key:value` pairs joined with `,`, see RegisterParser.
+func ParseFile(filePath string, data []byte, mapKeys ...string) (map[string]string, error) {
+	var (
+		doc map[string]interface{}
+		err error
+	)
+
+	switch ext := path.Ext(filePath); ext {
+	case ".env":
+		return ParseDotenv(data)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &doc)
+	case ".toml":
+		err = toml.Unmarshal(data, &doc)
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&doc)
+	default:
+		return nil, fmt.Errorf("unsupported config file extension %q", ext)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]bool, len(mapKeys))
+	for _, k := range mapKeys {
+		keys[k] = true
+	}
+
+	vars := map[string]string{}
+	if err := flatten(vars, doc, "", keys); err != nil {
+		return nil, err
+	}
+
+	return vars, nil
+}
+
+// readConfigFile reads, decrypts and parses the config file.
+func readConfigFile(fsys fs.FS, configFilePath string, decrypter SecretDecrypter, mapKeys []string) (map[string]string, error) {
+	format, ok := sopsFormats[path.Ext(configFilePath)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported config file extension %q", path.Ext(configFilePath))
+	}
+
+	data, err := readFile(fsys, configFilePath, decrypter, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return map[string]string{}, nil
+	}
+
+	vars, err := ParseFile(configFilePath, data, mapKeys...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse '%s': %w", configFilePath, err)
+	}
+
+	if err := decryptValues(vars, decrypter); err != nil {
+		return nil, fmt.Errorf("unable to decrypt '%s': %w", configFilePath, err)
+	}
+
+	return vars, nil
+}
+
+func flatten(vars map[string]string, doc map[string]interface{}, prefix string, mapKeys map[string]bool) error {
+	for k, v := range doc {
+		key := prefix + envKey(k)
+		if m, ok := v.(map[string]interface{}); ok && !mapKeys[key] {
+			if err := flatten(vars, m, key+"_", mapKeys); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, err := stringify(v)
+		if err != nil {
+			return fmt.Errorf("key %q: %w", key, err)
+		}
+		vars[key] = value
+	}
+
+	return nil
+}
+
+func stringify(v interface{}) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return val, nil
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), nil
+	case time.Time:
+		return val.Format(time.RFC3339Nano), nil
+	case []interface{}:
+		items := make([]string, 0, len(val))
+		for _, item := range val {
+			s, err := stringify(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return strings.Join(items, ","), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		items := make([]string, 0, len(val))
+		for _, k := range keys {
+			s, err := stringifyMapValue(val[k])
+			if err != nil {
+				return "", err
+			}
+			items = append(items, escape(k, ",:")+":"+s)
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return fmt.Sprint(val), nil
+	}
+}
+
+// stringifyMapValue encodes a map value for the map parsers, i.e. escaped with the lists joined
+// with `|`.
+func stringifyMapValue(v interface{}) (string, error) {
+	list, ok := v.([]interface{})
+	if !ok {
+		s, err := stringify(v)
+		return escape(s, ","), err
+	}
+
+	items := make([]string, 0, len(list))
+	for _, item := range list {
+		s, err := stringify(item)
+		if err != nil {
+			return "", err
+		}
+		items = append(items, escape(s, ",|"))
+	}
+
+	return strings.Join(items, "|"), nil
+}
+
+// envKey converts a YAML, TOML or JSON key to its environment variable form.
+func envKey(k string) string {
+	return strings.NewReplacer("-", "_", ".", "_").Replace(strings.ToUpper(k))
+}
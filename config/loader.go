@@ -0,0 +1,315 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/caarlos0/env/v6"
+)
+
+// Source indicates the configuration layer that a value was loaded from.
+type Source string
+
+const (
+	// SourceUnset indicates that no layer provides the value.
+	SourceUnset Source = "unset"
+
+	// SourceDefault indicates that the value comes from the field's `envDefault` tag.
+	SourceDefault Source = "default"
+
+	// SourceBaseFile indicates that the value comes from the base file shared by every environment.
+	SourceBaseFile Source = "base_file"
+
+	// SourceEnvFile indicates that the value comes from the per-environment overlay file.
+	SourceEnvFile Source = "env_file"
+
+	// SourceEnv indicates that the value comes from the process environment variables.
+	SourceEnv Source = "env"
+
+	// SourceFlag indicates that the value comes from the command-line flags.
+	SourceFlag Source = "flag"
+)
+
+// Origin describes where the final value of a config field came from.
+type Origin struct {
+	// Field is the dotted Go path of the field, e.g. "DB.URI".
+	Field string
+
+	// Source is the layer that the value was loaded from.
+	Source Source
+
+	// Location is the file path or the flag name that the value was read from, if any.
+	Location string
+}
+
+// Provenance maps each config field's environment variable name to the origin of its value.
+type Provenance map[string]Origin
+
+// LoaderConfig indicates how the layered config loader should be initialised.
+type LoaderConfig struct {
+	// Args are the command-line arguments to read the flags from, without the program name. Each
+	// field can be set with `--<key>=<value>` or `--<key> <value>` where the key is the field's
+	// environment variable name in lower case with `_` replaced by `-`, e.g. `--db-uri`. Unknown
+	// flags are ignored. By default, no flag is read.
+	Args []string
+
+	// Decrypter decrypts the config files and their `ENC[...]` values. By default, it is selected
+	// by the `CONFIG_DECRYPTER*` environment variables (see DecrypterConfig) or, when they are
+	// unset, SOPS is used as long as SopsFile exists and Env isn't "development".
+	Decrypter SecretDecrypter
+
+	// BaseFile is the name of the file in Dir that is loaded for every environment. It is
+	// optional. When it has no extension, the first file found with one of FileExtensions is
+	// loaded. By default, it is "base".
+	BaseFile string
+
+	// Dir is the directory in FS holding the config files. By default, it is "configs".
+	Dir string
+
+	// Env indicates the environment whose overlay file `<Env>` in Dir, with the first of
+	// FileExtensions found, is loaded on top of the base file. It is optional. By default, it is
+	// the `APP_ENV` environment variable.
+	Env string
+
+	// FS is the file system holding the config files. By default, it is the OS file system rooted
+	// at the current working directory.
+	FS fs.FS
+
+	// SopsFile is the path in FS of the SOPS config, see Decrypter. By default, it is ".sops.yaml".
+	SopsFile string
+}
+
+// Loader loads a config struct from layered sources. From the lowest to the highest precedence,
+// the layers are:
+//   - the `envDefault` struct tags
+//   - the base file
+//   - the per-environment overlay file
+//   - the process environment variables
+//   - the command-line flags
+type Loader struct {
+	config *LoaderConfig
+}
+
+type layer struct {
+	source   Source
+	location string
+	vars     map[string]string
+}
+
+// NewLoader initialises a layered config loader.
+func NewLoader(c *LoaderConfig) *Loader {
+	return &Loader{
+		config: defaultLoaderConfig(c),
+	}
+}
+
+// Load parses the layered sources into the config struct pointer c, validates the result (see
+// Validate) and reports where the final value of every field came from.
+func (l *Loader) Load(c interface{}) (Provenance, error) {
+	layers, err := l.layers(c)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := map[string]string{}
+	origins := map[string]Origin{}
+	for _, ly := range layers {
+		for k, v := range ly.vars {
+			merged[k] = v
+			origins[k] = Origin{Source: ly.source, Location: ly.location}
+		}
+	}
+
+	paths := map[string]string{}
+	for _, f := range structFields(c) {
+		paths[f.Key] = f.Path
+	}
+
+	provenance := Provenance{}
+	err = parseEnv(c, env.Options{
+		Environment: merged,
+		OnSet: func(key string, value interface{}, isDefault bool) {
+			fieldPath, known := paths[key]
+			if !known {
+				return
+			}
+
+			origin, ok := origins[key]
+			switch {
+			case isDefault:
+				origin = Origin{Source: SourceDefault}
+			case !ok:
+				origin = Origin{Source: SourceUnset}
+			}
+			origin.Field = fieldPath
+			provenance[key] = origin
+		},
+	})
+	if err != nil {
+		return provenance, err
+	}
+
+	return provenance, Validate(c)
+}
+
+func (l *Loader) layers(c interface{}) ([]layer, error) {
+	fields := structFields(c)
+	mapKeys := []string{}
+	for _, f := range fields {
+		if f.StructField.Type.Kind() == reflect.Map {
+			mapKeys = append(mapKeys, f.Key)
+		}
+	}
+
+	decrypter, err := resolveDecrypter(l.config.Decrypter, l.config.FS, l.config.SopsFile, l.config.Env)
+	if err != nil {
+		return nil, err
+	}
+
+	layers := []layer{}
+	files := []struct {
+		source Source
+		name   string
+	}{
+		{SourceBaseFile, l.config.BaseFile},
+		{SourceEnvFile, l.config.Env},
+	}
+	for _, f := range files {
+		if f.name == "" {
+			continue
+		}
+
+		filePath, err := l.lookupFile(f.name)
+		if err != nil {
+			return nil, err
+		}
+		if filePath == "" {
+			continue
+		}
+
+		vars, err := readConfigFile(l.config.FS, filePath, decrypter, mapKeys)
+		if err != nil {
+			return nil, err
+		}
+
+		layers = append(layers, layer{f.source, filePath, vars})
+	}
+
+	envs := map[string]string{}
+	for _, kv := range os.Environ() {
+		splits := strings.SplitN(kv, "=", 2)
+		if len(splits) == 2 && splits[1] != "" {
+			envs[splits[0]] = splits[1]
+		}
+	}
+	layers = append(layers, layer{SourceEnv, "", envs})
+
+	flags, err := parseFlags(l.config.Args, fields)
+	if err != nil {
+		return nil, err
+	}
+	for name, kv := range flags {
+		layers = append(layers, layer{SourceFlag, "--" + name, kv})
+	}
+
+	return layers, nil
+}
+
+// lookupFile returns the path of the config file in Dir, or an empty path when it doesn't exist.
+// When the name has no extension, the first existing file with one of FileExtensions is used.
+func (l *Loader) lookupFile(name string) (string, error) {
+	candidates := []string{name}
+	if path.Ext(name) == "" {
+		candidates = []string{}
+		for _, ext := range FileExtensions {
+			candidates = append(candidates, name+ext)
+		}
+	}
+
+	for _, candidate := range candidates {
+		filePath := path.Join(l.config.Dir, candidate)
+		_, err := fs.Stat(l.config.FS, filePath)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		return filePath, nil
+	}
+
+	return "", nil
+}
+
+// parseFlags returns the values of the known config flags found in args, grouped by flag name.
+func parseFlags(args []string, fields []field) (map[string]map[string]string, error) {
+	keys := map[string]string{}
+	for _, f := range fields {
+		keys[flagName(f.Key)] = f.Key
+	}
+
+	flags := map[string]map[string]string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		key, ok := keys[name]
+		if !ok {
+			continue
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("flag needs an argument: --%s", name)
+			}
+			i++
+			value = args[i]
+		}
+
+		flags[name] = map[string]string{key: value}
+	}
+
+	return flags, nil
+}
+
+// flagName converts an environment variable name to its flag name, e.g. "DB_URI" to "db-uri".
+func flagName(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "_", "-")
+}
+
+func defaultLoaderConfig(c *LoaderConfig) *LoaderConfig {
+	if c.BaseFile == "" {
+		c.BaseFile = "base"
+	}
+
+	if c.Dir == "" {
+		c.Dir = "configs"
+	}
+
+	if c.Env == "" {
+		c.Env = os.Getenv("APP_ENV")
+	}
+
+	if c.FS == nil {
+		c.FS = os.DirFS(".")
+	}
+
+	if c.SopsFile == "" {
+		c.SopsFile = ".sops.yaml"
+	}
+
+	return c
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type loaderTestConfig struct {
+	Name     string `env:"NAME" envDefault:"app"`
+	LogLevel string `env:"LOG_LEVEL" envDefault:"info"`
+	Port     int    `env:"PORT"`
+	Missing  string `env:"MISSING"`
+	DB       struct {
+		URI      string `env:"URI"`
+		MaxConns int    `env:"MAX_CONNS" envDefault:"16"`
+	} `envPrefix:"DB_"`
+}
+
+func Test_Loader_Load(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/base.env":    {Data: []byte("NAME=base\nPORT=8080\nDB_URI=postgres://base\n")},
+		"configs/staging.env": {Data: []byte("# overlay\nDB_URI=postgres://staging\nLOG_LEVEL=warn\n")},
+	}
+
+	os.Setenv("PORT", "9090")
+	defer os.Unsetenv("PORT")
+
+	c := loaderTestConfig{}
+	provenance, err := NewLoader(&LoaderConfig{
+		Args: []string{"serve", "--unknown", "--log-level", "debug", "--db-max-conns=32"},
+		Env:  "staging",
+		FS:   fsys,
+	}).Load(&c)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "base", c.Name)
+	assert.Equal(t, "debug", c.LogLevel)
+	assert.Equal(t, 9090, c.Port)
+	assert.Equal(t, "postgres://staging", c.DB.URI)
+	assert.Equal(t, 32, c.DB.MaxConns)
+	assert.Equal(t, Provenance{
+		"NAME":         {Field: "Name", Source: SourceBaseFile, Location: "configs/base.env"},
+		"LOG_LEVEL":    {Field: "LogLevel", Source: SourceFlag, Location: "--log-level"},
+		"PORT":         {Field: "Port", Source: SourceEnv},
+		"MISSING":      {Field: "Missing", Source: SourceUnset},
+		"DB_URI":       {Field: "DB.URI", Source: SourceEnvFile, Location: "configs/staging.env"},
+		"DB_MAX_CONNS": {Field: "DB.MaxConns", Source: SourceFlag, Location: "--db-max-conns"},
+	}, provenance)
+}
+
+func Test_Loader_Load_WithoutFiles(t *testing.T) {
+	c := loaderTestConfig{}
+	provenance, err := NewLoader(&LoaderConfig{
+		Env: "production",
+		FS:  fstest.MapFS{},
+	}).Load(&c)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "app", c.Name)
+	assert.Equal(t, Origin{Field: "DB.MaxConns", Source: SourceDefault}, provenance["DB_MAX_CONNS"])
+}
+
+func Test_Loader_Load_InvalidFile(t *testing.T) {
+	c := loaderTestConfig{}
+	_, err := NewLoader(&LoaderConfig{
+		Env: "development",
+		FS:  fstest.MapFS{"configs/development.env": {Data: []byte("NAME=a\nBROKEN\n")}},
+	}).Load(&c)
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "configs/development.env")
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+type fileTestConfig struct {
+	Name    string            `env:"NAME"`
+	Hosts   []string          `env:"HOSTS"`
+	Labels  map[string]string `env:"LABELS"`
+	Timeout time.Duration     `env:"TIMEOUT"`
+	DB      struct {
+		URI      string  `env:"URI"`
+		MaxConns int     `env:"MAX_CONNS"`
+		Ratio    float64 `env:"RATIO"`
+	} `envPrefix:"DB_"`
+}
+
+func Test_Loader_Load_StructuredFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+	}{
+		{
+			name: "when the config file is YAML",
+			file: "configs/base.yaml",
+			data: "name: app\nhosts: [a, b]\nlabels:\n  team: core\n  tier: web\ntimeout: 5s\ndb:\n  uri: postgres://localhost\n  max-conns: 16\n  ratio: 0.5\n",
+		},
+		{
+			name: "when the config file is TOML",
+			file: "configs/base.toml",
+			data: "name = \"app\"\nhosts = [\"a\", \"b\"]\ntimeout = \"5s\"\n[labels]\nteam = \"core\"\ntier = \"web\"\n[db]\nuri = \"postgres://localhost\"\nmax_conns = 16\nratio = 0.5\n",
+		},
+		{
+			name: "when the config file is JSON",
+			file: "configs/base.json",
+			data: `{"name": "app", "hosts": ["a", "b"], "labels": {"team": "core", "tier": "web"}, "timeout": "5s", "db": {"uri": "postgres://localhost", "max_conns": 16, "ratio": 0.5}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fileTestConfig{}
+			provenance, err := NewLoader(&LoaderConfig{
+				Env: "development",
+				FS:  fstest.MapFS{tt.file: {Data: []byte(tt.data)}},
+			}).Load(&c)
+
+			assert.Nil(t, err)
+			assert.Equal(t, "app", c.Name)
+			assert.Equal(t, []string{"a", "b"}, c.Hosts)
+			assert.Equal(t, map[string]string{"team": "core", "tier": "web"}, c.Labels)
+			assert.Equal(t, 5*time.Second, c.Timeout)
+			assert.Equal(t, "postgres://localhost", c.DB.URI)
+			assert.Equal(t, 16, c.DB.MaxConns)
+			assert.Equal(t, 0.5, c.DB.Ratio)
+			assert.Equal(t, Origin{Field: "DB.MaxConns", Source: SourceBaseFile, Location: tt.file}, provenance["DB_MAX_CONNS"])
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env/v6"
+)
+
+// ParserFunc parses an environment variable value into a value of the type it is registered for.
+type ParserFunc func(v string) (interface{}, error)
+
+var (
+	parsersMu sync.RWMutex
+	parsers   = map[reflect.Type]ParserFunc{
+		reflect.TypeOf([]byte{}):                   parseByteArray,
+		reflect.TypeOf([][]byte{}):                 parseByte2DArray,
+		reflect.TypeOf(map[string]int{}):           parseMapStrInt,
+		reflect.TypeOf(map[string]string{}):        parseMapStrStr,
+		reflect.TypeOf(map[string][]string{}):      parseMapStrStrs,
+		reflect.TypeOf(map[string]time.Duration{}): parseMapStrDuration,
+		reflect.TypeOf(http.SameSite(1)):           parseHTTPSameSite,
+		reflect.TypeOf(url.URL{}):                  parseURL,
+		reflect.TypeOf(net.IPNet{}):                parseIPNet,
+		reflect.TypeOf(regexp.Regexp{}):            parseRegexp,
+		reflect.TypeOf(ByteSize(0)):                parseByteSize,
+		reflect.TypeOf(tls.Certificate{}):          parseTLSCertificate,
+	}
+)
+
+// RegisterParser registers the parser for the config fields of type t, or of type *t, replacing
+// any existing parser of the type. The parser must return a value of type t, e.g.
+//
+//	config.RegisterParser(reflect.TypeOf(zapcore.Level(0)), func(v string) (interface{}, error) {
+//		return zapcore.ParseLevel(v)
+//	})
+//
+// The built-in parsers support `[]byte`, `[][]byte`, `map[string]int`, `map[string]string`,
+// `map[string][]string`, `map[string]time.Duration`, `http.SameSite`, `url.URL`, `net.IPNet`,
+// `regexp.Regexp`, `ByteSize` and `tls.Certificate`. The map entries are written as `key:value`
+// pairs joined with `,`, e.g. `api:https://api.example.com,auth:https://auth.example.com` as only
+// the first `:` of an entry separates its key, and the `[]string` map values are joined with `|`.
+// A `,`, `:`, `|` or `\` that is part of a key or a value is escaped with a `\`.
+func RegisterParser(t reflect.Type, fn ParserFunc) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+
+	if fn == nil {
+		delete(parsers, t)
+		return
+	}
+	parsers[t] = fn
+}
+
+// envParsers returns a copy of the registered parsers for the env library.
+func envParsers() map[reflect.Type]env.ParserFunc {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+
+	funcs := make(map[reflect.Type]env.ParserFunc, len(parsers))
+	for t, fn := range parsers {
+		funcs[t] = env.ParserFunc(fn)
+	}
+
+	return funcs
+}
+
+// ParseMap parses the `key:value` entries joined with `,` the same way as the `map[string]string`
+// fields, for the custom parsers, see RegisterParser.
+func ParseMap(v string) (map[string]string, error) {
+	return parseMap(v, func(s string) (string, error) {
+		return unescape(s), nil
+	})
+}
+
+// ParseList parses the items joined with `|` the same way as the `map[string][]string` field
+// values, for the custom parsers, see RegisterParser.
+func ParseList(v string) []string {
+	items := []string{}
+	for _, item := range splitEscaped(v, '|', -1) {
+		items = append(items, unescape(item))
+	}
+
+	return items
+}
+
+// ByteSize is a number of bytes parsed from a size such as `512`, `10MB` or `1.5GiB`. The KB, MB,
+// GB and TB units are powers of 1000 and the KiB, MiB, GiB and TiB units are powers of 1024.
+type ByteSize int64
+
+// The ByteSize units.
+const (
+	Byte ByteSize = 1
+
+	KB = 1000 * Byte
+	MB = 1000 * KB
+	GB = 1000 * MB
+	TB = 1000 * GB
+
+	KiB = 1024 * Byte
+	MiB = 1024 * KiB
+	GiB = 1024 * MiB
+	TiB = 1024 * GiB
+)
+
+var byteSizeUnits = []struct {
+	name string
+	size ByteSize
+}{
+	{"TiB", TiB}, {"TB", TB}, {"GiB", GiB}, {"GB", GB}, {"MiB", MiB}, {"MB", MB}, {"KiB", KiB}, {"KB", KB}, {"B", Byte},
+}
+
+// ParseByteSize parses a size such as `512`, `10MB` or `1.5GiB`, the units are case-insensitive.
+func ParseByteSize(v string) (ByteSize, error) {
+	s := strings.TrimSpace(v)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i == -1 {
+		i = len(s)
+	}
+
+	number, unit := s[:i], strings.TrimSpace(s[i:])
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q", v)
+	}
+
+	size := Byte
+	if unit != "" {
+		size = 0
+		for _, u := range byteSizeUnits {
+			if strings.EqualFold(unit, u.name) {
+				size = u.size
+				break
+			}
+		}
+		if size == 0 {
+			return 0, fmt.Errorf("invalid byte size %q: unknown unit %q", v, unit)
+		}
+	}
+
+	bytes := n * float64(size)
+	if bytes > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid byte size %q: out of range", v)
+	}
+
+	return ByteSize(bytes), nil
+}
+
+// String returns the size in the largest unit that represents it exactly, e.g. `10MB`.
+func (b ByteSize) String() string {
+	for _, u := range byteSizeUnits {
+		if b != 0 && b%u.size == 0 {
+			return strconv.FormatInt(int64(b/u.size), 10) + u.name
+		}
+	}
+
+	return strconv.FormatInt(int64(b), 10) + "B"
+}
+
+func parseByteSize(v string) (interface{}, error) {
+	return ParseByteSize(v)
+}
+
+func parseMapStrStrs(v string) (interface{}, error) {
+	return parseMap(v, func(s string) ([]string, error) {
+		return ParseList(s), nil
+	})
+}
+
+func parseMapStrDuration(v string) (interface{}, error) {
+	return parseMap(v, func(s string) (time.Duration, error) {
+		return time.ParseDuration(unescape(s))
+	})
+}
+
+func parseURL(v string) (interface{}, error) {
+	u, err := url.Parse(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return *u, nil
+}
+
+func parseIPNet(v string) (interface{}, error) {
+	_, ipNet, err := net.ParseCIDR(strings.TrimSpace(v))
+	if err != nil {
+		return nil, err
+	}
+
+	return *ipNet, nil
+}
+
+func parseRegexp(v string) (interface{}, error) {
+	re, err := regexp.Compile(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return *re, nil
+}
+
+// parseTLSCertificate loads the X509 key pair from the `<cert file>,<key file>` paths, or from a
+// single PEM file holding both.
+func parseTLSCertificate(v string) (interface{}, error) {
+	paths := splitEscaped(v, ',', 2)
+	certFile := unescape(paths[0])
+	keyFile := certFile
+	if len(paths) == 2 {
+		keyFile = unescape(paths[1])
+	}
+
+	return tls.LoadX509KeyPair(certFile, keyFile)
+}
+
+// parseMap parses the `key:value` entries joined with `,`, only the first unescaped `:` of an entry
+// separates its key from its value.
+func parseMap[V any](v string, parseValue func(s string) (V, error)) (map[string]V, error) {
+	newMaps := map[string]V{}
+	for _, entry := range splitEscaped(v, ',', -1) {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		splits := splitEscaped(entry, ':', 2)
+		if len(splits) != 2 {
+			return nil, fmt.Errorf("invalid map entry %q, expected key:value", entry)
+		}
+
+		val, err := parseValue(splits[1])
+		if err != nil {
+			return nil, err
+		}
+
+		newMaps[unescape(splits[0])] = val
+	}
+
+	return newMaps, nil
+}
+
+// splitEscaped splits s around the separators that aren't escaped with a `\`, into n parts at most
+// if n >= 0. The escapes are kept in the parts.
+func splitEscaped(s string, sep byte, n int) []string {
+	parts := []string{}
+	start := 0
+	for i := 0; i < len(s) && (n < 0 || len(parts) < n-1); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+// escape escapes the `\` and the chars with a `\`.
+func escape(s, chars string) string {
+	if !strings.ContainsAny(s, chars+`\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' || strings.IndexByte(chars, s[i]) >= 0 {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+// unescape removes the `\` escaping the next character.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
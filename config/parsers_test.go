@@ -0,0 +1,140 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parsersTestLevel int
+
+type parsersTestConfig struct {
+	Backends      map[string]string        `env:"PARSERS_TEST_BACKENDS"`
+	Conns         map[string]int           `env:"PARSERS_TEST_CONNS"`
+	Origins       map[string][]string      `env:"PARSERS_TEST_ORIGINS"`
+	Timeouts      map[string]time.Duration `env:"PARSERS_TEST_TIMEOUTS"`
+	URL           url.URL                  `env:"PARSERS_TEST_URL"`
+	TrustedProxy  net.IPNet                `env:"PARSERS_TEST_TRUSTED_PROXY"`
+	AllowedRoutes *regexp.Regexp           `env:"PARSERS_TEST_ALLOWED_ROUTES"`
+	MaxBodySize   ByteSize                 `env:"PARSERS_TEST_MAX_BODY_SIZE"`
+	Certificate   tls.Certificate          `env:"PARSERS_TEST_CERTIFICATE"`
+	Level         parsersTestLevel         `env:"PARSERS_TEST_LEVEL"`
+}
+
+func Test_ParseAppConfig_Parsers(t *testing.T) {
+	certFile, keyFile := writeTestKeyPair(t)
+
+	RegisterParser(reflect.TypeOf(parsersTestLevel(0)), func(v string) (interface{}, error) {
+		return parsersTestLevel(len(v)), nil
+	})
+	defer RegisterParser(reflect.TypeOf(parsersTestLevel(0)), nil)
+
+	t.Setenv("PARSERS_TEST_BACKENDS", `api:https://api.example.com:8443,odd\:key:a\,b`)
+	t.Setenv("PARSERS_TEST_CONNS", "read:4,write:2")
+	t.Setenv("PARSERS_TEST_ORIGINS", "web:https://a.com|https://b.com,mobile:app://c")
+	t.Setenv("PARSERS_TEST_TIMEOUTS", "read:5s,write:1m")
+	t.Setenv("PARSERS_TEST_URL", "postgres://db:5432/app?sslmode=disable")
+	t.Setenv("PARSERS_TEST_TRUSTED_PROXY", "10.0.0.0/8")
+	t.Setenv("PARSERS_TEST_ALLOWED_ROUTES", `^/api/v\d+/`)
+	t.Setenv("PARSERS_TEST_MAX_BODY_SIZE", "1.5MiB")
+	t.Setenv("PARSERS_TEST_CERTIFICATE", certFile+","+keyFile)
+	t.Setenv("PARSERS_TEST_LEVEL", "debug")
+
+	c := parsersTestConfig{}
+	assert.Nil(t, ParseAppConfig(&c))
+
+	assert.Equal(t, map[string]string{"api": "https://api.example.com:8443", "odd:key": "a,b"}, c.Backends)
+	assert.Equal(t, map[string]int{"read": 4, "write": 2}, c.Conns)
+	assert.Equal(t, map[string][]string{"web": {"https://a.com", "https://b.com"}, "mobile": {"app://c"}}, c.Origins)
+	assert.Equal(t, map[string]time.Duration{"read": 5 * time.Second, "write": time.Minute}, c.Timeouts)
+	assert.Equal(t, "db:5432", c.URL.Host)
+	assert.True(t, c.TrustedProxy.Contains(net.ParseIP("10.1.2.3")))
+	assert.True(t, c.AllowedRoutes.MatchString("/api/v2/users"))
+	assert.Equal(t, ByteSize(1572864), c.MaxBodySize)
+	assert.Len(t, c.Certificate.Certificate, 1)
+	assert.Equal(t, parsersTestLevel(5), c.Level)
+
+	t.Run("should fail on a map entry without a key", func(t *testing.T) {
+		t.Setenv("PARSERS_TEST_BACKENDS", "api")
+
+		err := ParseAppConfig(&parsersTestConfig{})
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), `invalid map entry "api", expected key:value`)
+	})
+}
+
+func Test_ParseByteSize(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    ByteSize
+		wantErr bool
+	}{
+		{value: "512", want: 512},
+		{value: "10MB", want: 10 * MB},
+		{value: "10 mb", want: 10 * MB},
+		{value: "2GiB", want: 2 * GiB},
+		{value: "1.5KB", want: 1500},
+		{value: "MB", wantErr: true},
+		{value: "10XB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseByteSize(tt.value)
+		assert.Equal(t, tt.wantErr, err != nil, tt.value)
+		assert.Equal(t, tt.want, got, tt.value)
+	}
+
+	assert.Equal(t, "10MB", (10 * MB).String())
+	assert.Equal(t, "3KiB", (3 * KiB).String())
+	assert.Equal(t, "0B", ByteSize(0).String())
+}
+
+func Test_ParseFile_MapEscaping(t *testing.T) {
+	vars, err := ParseFile("base.yaml", []byte("backends:\n  a,b: \"x:y,z\"\norigins:\n  web: [\"https://a.com\", \"https://b.com\"]\n"), "BACKENDS", "ORIGINS")
+	assert.Nil(t, err)
+
+	backends, err := parseMapStrStr(vars["BACKENDS"])
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"a,b": "x:y,z"}, backends)
+
+	origins, err := parseMapStrStrs(vars["ORIGINS"])
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]string{"web": {"https://a.com", "https://b.com"}}, origins)
+}
+
+func writeTestKeyPair(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	assert.Nil(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.Nil(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	return certFile, keyFile
+}
@@ -0,0 +1,229 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// ReloaderConfig indicates how the hot-reloadable config should be initialised.
+type ReloaderConfig struct {
+	// Loader indicates how the config is loaded on every reload. By default, the config files are
+	// read from the "configs" directory on disk.
+	Loader LoaderConfig
+
+	// OnError is called with the error of a failed reload, the current config is kept in that case.
+	OnError func(err error)
+
+	// PollInterval indicates how often the config directory is checked for changes, the config is
+	// reloaded when the content of any file in it changes. By default, it is 5 * time.Second.
+	PollInterval time.Duration
+
+	// Signals are the signals that trigger a reload. By default, it is SIGHUP.
+	Signals []os.Signal
+}
+
+// Reloader holds a config struct of type T that is reloaded from disk when its directory changes
+// or when the process receives a SIGHUP. A reloaded config is parsed and validated before it is
+// swapped in atomically, so Get never returns a partially loaded or invalid config.
+type Reloader[T any] struct {
+	config      *ReloaderConfig
+	loader      *Loader
+	current     atomic.Pointer[T]
+	provenance  atomic.Pointer[Provenance]
+	mu          sync.Mutex
+	fingerprint string
+	nextID      int
+	subscribers map[int]func(old, new *T)
+}
+
+// NewReloader initialises the hot-reloadable config and loads it for the first time.
+func NewReloader[T any](c *ReloaderConfig) (*Reloader[T], error) {
+	r := &Reloader[T]{
+		config:      defaultReloaderConfig(c),
+		subscribers: map[int]func(old, new *T){},
+	}
+	r.loader = NewLoader(&r.config.Loader)
+
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Get returns the current config, it must be treated as read-only.
+func (r *Reloader[T]) Get() *T {
+	return r.current.Load()
+}
+
+// Provenance returns where the current config values came from.
+func (r *Reloader[T]) Provenance() Provenance {
+	return *r.provenance.Load()
+}
+
+// Subscribe registers fn to be called with the previous and the new config every time a reload
+// changes the config. It returns a function that removes the subscription.
+func (r *Reloader[T]) Subscribe(fn func(old, new *T)) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := r.nextID
+	r.nextID++
+	r.subscribers[id] = fn
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.subscribers, id)
+	}
+}
+
+// Reload loads, parses and validates the config, swaps it in and notifies the subscribers if it
+// has changed. On error, the current config is kept.
+func (r *Reloader[T]) Reload() error {
+	old, next, err := r.swap()
+	if err != nil || old == nil || next == nil {
+		return err
+	}
+
+	r.mu.Lock()
+	subscribers := make([]func(old, new *T), 0, len(r.subscribers))
+	for _, fn := range r.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	r.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(old, next)
+	}
+
+	return nil
+}
+
+// swap loads the config and swaps it in, it returns a nil next config when nothing has changed.
+func (r *Reloader[T]) swap() (old, next *T, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fingerprint, err := r.dirFingerprint()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// an invalid config is retried on the next change only
+	r.fingerprint = fingerprint
+
+	next = new(T)
+	provenance, err := r.loader.Load(next)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.provenance.Store(&provenance)
+	old = r.current.Load()
+	if old != nil && reflect.DeepEqual(old, next) {
+		return old, nil, nil
+	}
+
+	r.current.Store(next)
+
+	return old, next, nil
+}
+
+// Watch reloads the config whenever the config directory changes or one of the signals is
+// received, until the context is done. Reload errors are reported to OnError.
+func (r *Reloader[T]) Watch(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, r.config.Signals...)
+	defer signal.Stop(signals)
+
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			r.reload()
+		case <-ticker.C:
+			fingerprint, err := r.dirFingerprint()
+			if err != nil {
+				r.config.OnError(err)
+				continue
+			}
+
+			r.mu.Lock()
+			changed := fingerprint != r.fingerprint
+			r.mu.Unlock()
+			if changed {
+				r.reload()
+			}
+		}
+	}
+}
+
+func (r *Reloader[T]) reload() {
+	if err := r.Reload(); err != nil {
+		r.config.OnError(err)
+	}
+}
+
+// dirFingerprint hashes the names and the contents of the files in the config directory.
+func (r *Reloader[T]) dirFingerprint() (string, error) {
+	fsys, dir := r.config.Loader.FS, r.config.Loader.Dir
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	for _, entry := range entries {
+		filePath := path.Join(dir, entry.Name())
+		info, err := fs.Stat(fsys, filePath)
+		if err != nil {
+			return "", err
+		}
+		if info.IsDir() {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, filePath)
+		if err != nil {
+			return "", err
+		}
+
+		h.Write([]byte(entry.Name()))
+		h.Write(data)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func defaultReloaderConfig(c *ReloaderConfig) *ReloaderConfig {
+	defaultLoaderConfig(&c.Loader)
+
+	if c.OnError == nil {
+		c.OnError = func(err error) {}
+	}
+
+	if c.PollInterval == 0 {
+		c.PollInterval = 5 * time.Second
+	}
+
+	if len(c.Signals) == 0 {
+		c.Signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	return c
+}
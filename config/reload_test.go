@@ -0,0 +1,102 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type reloadTestConfig struct {
+	LogLevel string `env:"RELOAD_TEST_LOG_LEVEL" validate:"oneof=debug info warn error"`
+	Burst    int    `env:"RELOAD_TEST_BURST" validate:"min=1"`
+}
+
+func Test_Reloader(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "base.env")
+	write := func(content string) {
+		assert.Nil(t, os.WriteFile(file, []byte(content), 0600))
+	}
+	write("RELOAD_TEST_LOG_LEVEL=info\nRELOAD_TEST_BURST=3\n")
+
+	var (
+		mu      sync.Mutex
+		changes [][2]reloadTestConfig
+		errs    []error
+	)
+	reloader, err := NewReloader[reloadTestConfig](&ReloaderConfig{
+		Loader: LoaderConfig{
+			Env: "development",
+			FS:  os.DirFS(dir),
+			Dir: ".",
+		},
+		OnError: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		},
+		PollInterval: 10 * time.Millisecond,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &reloadTestConfig{LogLevel: "info", Burst: 3}, reloader.Get())
+	assert.Equal(t, SourceBaseFile, reloader.Provenance()["RELOAD_TEST_BURST"].Source)
+
+	unsubscribe := reloader.Subscribe(func(old, new *reloadTestConfig) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, [2]reloadTestConfig{*old, *new})
+	})
+
+	t.Run("should notify the subscribers when the config changes", func(t *testing.T) {
+		write("RELOAD_TEST_LOG_LEVEL=debug\nRELOAD_TEST_BURST=3\n")
+		assert.Nil(t, reloader.Reload())
+
+		assert.Equal(t, "debug", reloader.Get().LogLevel)
+		assert.Equal(t, [][2]reloadTestConfig{{{"info", 3}, {"debug", 3}}}, changes)
+	})
+
+	t.Run("should not notify the subscribers when the config is unchanged", func(t *testing.T) {
+		assert.Nil(t, reloader.Reload())
+
+		assert.Len(t, changes, 1)
+	})
+
+	t.Run("should keep the current config when the new config is invalid", func(t *testing.T) {
+		write("RELOAD_TEST_LOG_LEVEL=verbose\nRELOAD_TEST_BURST=0\n")
+		err := reloader.Reload()
+
+		assert.NotNil(t, err)
+		assert.Equal(t, &reloadTestConfig{LogLevel: "debug", Burst: 3}, reloader.Get())
+		assert.Len(t, changes, 1)
+	})
+
+	t.Run("should reload when the directory changes while watching", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go reloader.Watch(ctx)
+
+		write("RELOAD_TEST_LOG_LEVEL=warn\nRELOAD_TEST_BURST=5\n")
+
+		assert.Eventually(t, func() bool {
+			return reloader.Get().Burst == 5
+		}, time.Second, 10*time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, reloadTestConfig{"warn", 5}, changes[len(changes)-1][1])
+	})
+
+	unsubscribe()
+	write("RELOAD_TEST_LOG_LEVEL=error\nRELOAD_TEST_BURST=5\n")
+	assert.Nil(t, reloader.Reload())
+	assert.Equal(t, "error", reloader.Get().LogLevel)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, reloadTestConfig{"warn", 5}, changes[len(changes)-1][1])
+}
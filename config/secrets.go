@@ -0,0 +1,166 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// SecretProvider resolves the secret references of a scheme, e.g. "file:///run/secrets/db_password"
+// for the "file" scheme.
+type SecretProvider interface {
+	// Resolve returns the secret value that the reference points to.
+	Resolve(ref *url.URL) (string, error)
+}
+
+// SecretProviderFunc is an adapter to use an ordinary function as a SecretProvider.
+type SecretProviderFunc func(ref *url.URL) (string, error)
+
+// Resolve implements SecretProvider.
+func (f SecretProviderFunc) Resolve(ref *url.URL) (string, error) {
+	return f(ref)
+}
+
+var (
+	secretProvidersMu sync.RWMutex
+	secretProviders   = map[string]SecretProvider{
+		"env":  EnvSecretProvider{},
+		"file": FileSecretProvider{},
+	}
+)
+
+// RegisterSecretProvider makes a secret provider available for the scheme, e.g. a provider
+// registered for "vault" resolves the values such as "vault://kv/db_password". Registering a
+// provider for an existing scheme replaces it, and a nil provider removes it.
+func RegisterSecretProvider(scheme string, provider SecretProvider) {
+	secretProvidersMu.Lock()
+	defer secretProvidersMu.Unlock()
+
+	if provider == nil {
+		delete(secretProviders, scheme)
+		return
+	}
+	secretProviders[scheme] = provider
+}
+
+// EnvSecretProvider resolves the references to other environment variables, e.g. "env://OTHER_VAR".
+type EnvSecretProvider struct {
+	// Environment indicates the variables to resolve the references from. When loading the config,
+	// it is the layered environment, i.e. the process environment with the config files and the
+	// flags. By default, it is nil and the process environment is used.
+	Environment map[string]string
+}
+
+// Resolve implements SecretProvider.
+func (p EnvSecretProvider) Resolve(ref *url.URL) (string, error) {
+	name := ref.Host + strings.TrimPrefix(ref.Path, "/")
+	lookup := os.LookupEnv
+	if p.Environment != nil {
+		lookup = func(key string) (string, bool) {
+			v, ok := p.Environment[key]
+			return v, ok
+		}
+	}
+
+	v, ok := lookup(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %q is not set", name)
+	}
+
+	return v, nil
+}
+
+// FileSecretProvider resolves the references to local files, e.g. "file:///run/secrets/db_password"
+// as mounted by Kubernetes or Docker secrets. A trailing newline in the file is dropped.
+type FileSecretProvider struct{}
+
+// Resolve implements SecretProvider.
+func (FileSecretProvider) Resolve(ref *url.URL) (string, error) {
+	b, err := os.ReadFile(ref.Host + ref.Path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(strings.TrimSuffix(string(b), "\n"), "\r"), nil
+}
+
+// MemorySecretProvider resolves the references from an in-memory map keyed by `<name>/<key>`,
+// e.g. "memory://db/password" resolves the "db/password" entry. It is useful for testing.
+type MemorySecretProvider struct {
+	mu      sync.RWMutex
+	secrets map[string]string
+}
+
+// NewMemorySecretProvider initialises a MemorySecretProvider with the secrets.
+func NewMemorySecretProvider(secrets map[string]string) *MemorySecretProvider {
+	p := &MemorySecretProvider{secrets: map[string]string{}}
+	for k, v := range secrets {
+		p.secrets[k] = v
+	}
+
+	return p
+}
+
+// Set adds or replaces a secret.
+func (p *MemorySecretProvider) Set(key, value string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.secrets[key] = value
+}
+
+// Resolve implements SecretProvider.
+func (p *MemorySecretProvider) Resolve(ref *url.URL) (string, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	key := ref.Host + ref.Path
+	v, ok := p.secrets[key]
+	if !ok {
+		return "", fmt.Errorf("secret %q not found", key)
+	}
+
+	return v, nil
+}
+
+// resolveSecretRefs replaces the values of the keys that are secret references with the secrets
+// they point to. Only the values whose scheme has a registered SecretProvider are resolved.
+func resolveSecretRefs(environment map[string]string, keys []string) error {
+	secretProvidersMu.RLock()
+	defer secretProvidersMu.RUnlock()
+
+	for _, key := range keys {
+		value, ok := environment[key]
+		if !ok {
+			continue
+		}
+
+		scheme, _, found := strings.Cut(value, "://")
+		if !found {
+			continue
+		}
+
+		provider, ok := secretProviders[scheme]
+		if !ok {
+			continue
+		}
+		if _, ok := provider.(EnvSecretProvider); ok {
+			provider = EnvSecretProvider{Environment: environment}
+		}
+
+		ref, err := url.Parse(value)
+		if err != nil {
+			return fmt.Errorf("invalid secret reference in %q: %w", key, err)
+		}
+
+		secret, err := provider.Resolve(ref)
+		if err != nil {
+			return fmt.Errorf("unable to resolve the %s secret reference in %q: %w", scheme, key, err)
+		}
+		environment[key] = secret
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type secretsTestConfig struct {
+	DBPassword string `env:"SECRETS_TEST_DB_PASSWORD"`
+	APIKey     string `env:"SECRETS_TEST_API_KEY"`
+	Token      string `env:"SECRETS_TEST_TOKEN"`
+	URL        string `env:"SECRETS_TEST_URL"`
+}
+
+func Test_ParseAppConfig_SecretRefs(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "db_password")
+	assert.Nil(t, os.WriteFile(secretFile, []byte("s3cret\n"), 0600))
+
+	RegisterSecretProvider("memory", NewMemorySecretProvider(map[string]string{"api/key": "k3y"}))
+	defer RegisterSecretProvider("memory", nil)
+
+	t.Setenv("SECRETS_TEST_DB_PASSWORD", "file://"+secretFile)
+	t.Setenv("SECRETS_TEST_API_KEY", "memory://api/key")
+	t.Setenv("SECRETS_TEST_TOKEN", "env://SECRETS_TEST_OTHER_TOKEN")
+	t.Setenv("SECRETS_TEST_OTHER_TOKEN", "t0ken")
+	t.Setenv("SECRETS_TEST_URL", "https://example.com")
+
+	c := secretsTestConfig{}
+	assert.Nil(t, ParseAppConfig(&c))
+	assert.Equal(t, secretsTestConfig{
+		DBPassword: "s3cret",
+		APIKey:     "k3y",
+		Token:      "t0ken",
+		URL:        "https://example.com",
+	}, c)
+
+	t.Run("should fail when the secret is not found", func(t *testing.T) {
+		t.Setenv("SECRETS_TEST_API_KEY", "memory://api/missing")
+
+		err := ParseAppConfig(&secretsTestConfig{})
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), `unable to resolve the memory secret reference in "SECRETS_TEST_API_KEY"`)
+	})
+}
+
+func Test_Loader_Load_SecretRefs(t *testing.T) {
+	RegisterSecretProvider("memory", NewMemorySecretProvider(map[string]string{"db/password": "s3cret"}))
+	defer RegisterSecretProvider("memory", nil)
+
+	c := secretsTestConfig{}
+	_, err := NewLoader(&LoaderConfig{
+		Env: "production",
+		FS: fstest.MapFS{
+			"configs/production.env": {Data: []byte("SECRETS_TEST_DB_PASSWORD=memory://db/password\n")},
+		},
+	}).Load(&c)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "s3cret", c.DBPassword)
+}
+
+func Test_Loader_Load_EnvSecretRefs(t *testing.T) {
+	c := secretsTestConfig{}
+	_, err := NewLoader(&LoaderConfig{
+		Env: "production",
+		FS: fstest.MapFS{
+			"configs/base.env":       {Data: []byte("SECRETS_TEST_OTHER_TOKEN=t0ken\n")},
+			"configs/production.env": {Data: []byte("SECRETS_TEST_TOKEN=env://SECRETS_TEST_OTHER_TOKEN\n")},
+		},
+	}).Load(&c)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "t0ken", c.Token)
+}
@@ -0,0 +1,222 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validator is implemented by the config structs that need validation beyond the `validate` tags.
+// It is called after the tag rules are checked.
+type Validator interface {
+	Validate() error
+}
+
+// FieldError describes a config field that failed a validation rule.
+type FieldError struct {
+	// Field is the dotted Go path of the field, e.g. "DB.URI".
+	Field string
+
+	// Key is the environment variable name of the field, e.g. "DB_URI".
+	Key string
+
+	// Rule is the validation rule that failed, e.g. "required".
+	Rule string
+
+	// Message describes why the rule failed.
+	Message string
+}
+
+// Error converts the field error to a human-readable string.
+func (e FieldError) Error() string {
+	if e.Key == "" {
+		return e.Message
+	}
+
+	return fmt.Sprintf("%s (%s): %s", e.Key, e.Field, e.Message)
+}
+
+// ValidationError aggregates every config field that failed validation.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// Error converts the validation error to a human-readable string listing every bad field.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		msgs = append(msgs, fe.Error())
+	}
+
+	return "config: invalid values: " + strings.Join(msgs, "; ")
+}
+
+// Validate checks the config struct against the rules in its `validate` tags and then calls its
+// Validate method, if any. The rules are separated by `,`:
+//   - required: the value must not be the zero value
+//   - min=<n>, max=<n>: numbers are compared by value, durations are compared with a duration such
+//     as `min=1s` and strings, slices and maps are compared by length
+//   - oneof=<a> <b> ...: the value must be one of the space-separated values
+//   - url: the value must be an absolute URL
+//   - hostport: the value must be a `host:port` address
+//   - duration>0: the duration must be positive
+//
+// Except for required, min, max and duration>0, the rules aren't checked for empty values. All
+// the failures are returned at once in a *ValidationError.
+func Validate(c interface{}) error {
+	verr := &ValidationError{}
+	for _, f := range structFields(c) {
+		tag, ok := f.StructField.Tag.Lookup("validate")
+		if !ok || tag == "" {
+			continue
+		}
+
+		for _, rule := range strings.Split(tag, ",") {
+			rule = strings.TrimSpace(rule)
+			if rule == "" {
+				continue
+			}
+
+			name, arg, _ := strings.Cut(rule, "=")
+			if msg := checkRule(f.Value, name, arg); msg != "" {
+				verr.Errors = append(verr.Errors, FieldError{
+					Field:   f.Path,
+					Key:     f.Key,
+					Rule:    name,
+					Message: msg,
+				})
+			}
+		}
+	}
+
+	if v, ok := c.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			var hookErr *ValidationError
+			if errors.As(err, &hookErr) {
+				verr.Errors = append(verr.Errors, hookErr.Errors...)
+			} else {
+				verr.Errors = append(verr.Errors, FieldError{Rule: "Validate", Message: err.Error()})
+			}
+		}
+	}
+
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+
+	return verr
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func checkRule(v reflect.Value, name, arg string) string {
+	if v.IsValid() && v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v = reflect.Value{}
+		} else {
+			v = v.Elem()
+		}
+	}
+
+	isZero := !v.IsValid() || v.IsZero()
+	switch name {
+	case "required":
+		if isZero {
+			return "is required"
+		}
+	case "min", "max":
+		return checkBound(v, name, arg)
+	case "duration>0":
+		if !v.IsValid() || v.Type() != durationType {
+			return "is not a duration"
+		}
+		if v.Int() <= 0 {
+			return "must be a positive duration"
+		}
+	case "oneof":
+		if isZero {
+			return ""
+		}
+		s := fmt.Sprint(v.Interface())
+		for _, option := range strings.Fields(arg) {
+			if s == option {
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be one of [%s]", strings.Join(strings.Fields(arg), ", "))
+	case "url":
+		if isZero {
+			return ""
+		}
+		u, err := url.Parse(fmt.Sprint(v.Interface()))
+		if err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "" && u.Path == "") {
+			return "must be an absolute URL"
+		}
+	case "hostport":
+		if isZero {
+			return ""
+		}
+		_, port, err := net.SplitHostPort(fmt.Sprint(v.Interface()))
+		if err != nil {
+			return "must be a host:port address"
+		}
+		if p, err := strconv.ParseUint(port, 10, 16); err != nil || p == 0 {
+			return "must have a port between 1 and 65535"
+		}
+	default:
+		return fmt.Sprintf("has an unknown validation rule %q", name)
+	}
+
+	return ""
+}
+
+func checkBound(v reflect.Value, name, arg string) string {
+	if !v.IsValid() {
+		return ""
+	}
+
+	var (
+		actual, bound float64
+		err           error
+		prefix        = "must be"
+	)
+
+	switch {
+	case v.Type() == durationType:
+		var d time.Duration
+		d, err = time.ParseDuration(arg)
+		actual, bound = float64(v.Int()), float64(d)
+	case v.Kind() == reflect.String, v.Kind() == reflect.Slice, v.Kind() == reflect.Map, v.Kind() == reflect.Array:
+		actual, prefix = float64(v.Len()), "must have a length"
+		bound, err = strconv.ParseFloat(arg, 64)
+	case v.CanInt():
+		actual = float64(v.Int())
+		bound, err = strconv.ParseFloat(arg, 64)
+	case v.CanUint():
+		actual = float64(v.Uint())
+		bound, err = strconv.ParseFloat(arg, 64)
+	case v.CanFloat():
+		actual = v.Float()
+		bound, err = strconv.ParseFloat(arg, 64)
+	default:
+		return fmt.Sprintf("does not support the %q rule", name)
+	}
+	if err != nil {
+		return fmt.Sprintf("has an invalid %q rule argument %q", name, arg)
+	}
+
+	if name == "min" && actual < bound {
+		return fmt.Sprintf("%s >= %s", prefix, arg)
+	}
+
+	if name == "max" && actual > bound {
+		return fmt.Sprintf("%s <= %s", prefix, arg)
+	}
+
+	return ""
+}
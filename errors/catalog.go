@@ -0,0 +1,177 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// InternalMessageKey is the catalog key of the translations of InternalMessage.
+const InternalMessageKey = "Internal"
+
+// catalogExtensions are the extensions of the message files, see LoadMessages.
+var catalogExtensions = map[string]bool{".yaml": true, ".yml": true, ".json": true}
+
+var (
+	catalogMu sync.RWMutex
+	catalog   = map[string]map[string]*template.Template{}
+)
+
+// RegisterMessages registers the translations of the app error messages for the locale, e.g. "hi"
+// or "hi-IN", adding to or replacing its existing translations. The messages are keyed by
+// `<type>.<code>`, e.g. "NotFound.USER_NOT_FOUND", by `<type>`, e.g. "NotFound", or by
+// InternalMessageKey, and are `text/template` templates whose parameters are the metadata of the
+// error, e.g.
+//
+//	errors.RegisterMessages("hi", map[string]string{
+//		"NotFound":                "रिकॉर्ड नहीं मिला",
+//		"NotFound.USER_NOT_FOUND": "उपयोगकर्ता {{.user_id}} नहीं मिला",
+//	})
+func RegisterMessages(locale string, messages map[string]string) error {
+	templates := make(map[string]*template.Template, len(messages))
+	for key, message := range messages {
+		tmpl, err := template.New(key).Option("missingkey=zero").Parse(message)
+		if err != nil {
+			return fmt.Errorf("unable to parse the %q message of the %q locale: %w", key, locale, err)
+		}
+		templates[key] = tmpl
+	}
+
+	locale = normalizeLocale(locale)
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+
+	if catalog[locale] == nil {
+		catalog[locale] = map[string]*template.Template{}
+	}
+	for key, tmpl := range templates {
+		catalog[locale][key] = tmpl
+	}
+
+	return nil
+}
+
+// LoadMessages registers the translations of the message files in the directory of the FS, one
+// YAML or JSON file of messages per locale named after it, e.g. "hi.yaml" or "ta-IN.json", see
+// RegisterMessages.
+func LoadMessages(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("unable to read the %q messages directory: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		if entry.IsDir() || !catalogExtensions[ext] {
+			continue
+		}
+
+		b, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("unable to read the %q messages file: %w", entry.Name(), err)
+		}
+
+		messages := map[string]string{}
+		if err := yaml.Unmarshal(b, &messages); err != nil {
+			return fmt.Errorf("unable to parse the %q messages file: %w", entry.Name(), err)
+		}
+
+		if err := RegisterMessages(strings.TrimSuffix(entry.Name(), ext), messages); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LocalizedMessage returns the message that the clients get in the first of the preferred locales
+// that has a translation of it, along with that locale. The translation of `<type>.<code>` is
+// preferred over the one of `<type>`, and the internal errors are translated with
+// InternalMessageKey. A locale with a region, e.g. "hi-IN", falls back to its language, e.g. "hi".
+// Without any translation, it returns PublicMessage and an empty locale.
+func (appErr *AppError) LocalizedMessage(locales ...string) (string, string) {
+	keys := []string{appErr.Type}
+	if appErr.Details.Code != "" {
+		keys = []string{appErr.Type + "." + appErr.Details.Code, appErr.Type}
+	}
+	if LookupType(appErr.Type).Internal {
+		keys = []string{InternalMessageKey}
+	}
+
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+
+	for _, locale := range locales {
+		locale = normalizeLocale(locale)
+		candidates := []string{locale}
+		if lang, _, ok := strings.Cut(locale, "-"); ok {
+			candidates = append(candidates, lang)
+		}
+
+		for _, candidate := range candidates {
+			for _, key := range keys {
+				tmpl, ok := catalog[candidate][key]
+				if !ok {
+					continue
+				}
+
+				var buf bytes.Buffer
+				if err := tmpl.Execute(&buf, appErr.Details.Metadata); err == nil {
+					return buf.String(), candidate
+				}
+			}
+		}
+	}
+
+	return appErr.PublicMessage(), ""
+}
+
+// ParseAcceptLanguage returns the locales of an `Accept-Language` header value, e.g.
+// "hi-IN,hi;q=0.9,en;q=0.8", ordered by preference. The wildcard is ignored.
+func ParseAcceptLanguage(value string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(value, ",") {
+		locale, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if locale == "" || locale == "*" {
+			continue
+		}
+
+		q := 1.0
+		if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+			if parsed, err := strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			tags = append(tags, weighted{locale, q})
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+
+	locales := make([]string, len(tags))
+	for i, tag := range tags {
+		locales[i] = tag.locale
+	}
+
+	return locales
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
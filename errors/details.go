@@ -0,0 +1,252 @@
+package errors
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/runtime/protoiface"
+)
+
+// violationRulePrefix prefixes the `google.rpc.ErrorInfo` metadata keys that carry the rules of the
+// field violations, as `google.rpc.BadRequest` has no room for them.
+const violationRulePrefix = "violation_rule:"
+
+// itemIndexKey and itemMessageKey are the `google.rpc.ErrorInfo` metadata keys of the index and of
+// the message of an item error, whose type and code are the domain and the reason of the ErrorInfo.
+const (
+	itemIndexKey   = "index"
+	itemMessageKey = "message"
+)
+
+// FieldViolation describes an invalid field of the request.
+type FieldViolation struct {
+	// Field is the path of the field, e.g. "address.zip_code".
+	Field string `json:"field"`
+
+	// Rule is the rule that the field violates, e.g. "required".
+	Rule string `json:"rule,omitempty"`
+
+	// Message is the human-readable description of the violation.
+	Message string `json:"message"`
+}
+
+// Details are the optional structured details of an app error.
+type Details struct {
+	// Code is a stable machine-readable code that identifies the error, e.g. "CARD_DECLINED".
+	Code string `json:"code,omitempty"`
+
+	// Violations are the invalid fields of the request.
+	Violations []FieldViolation `json:"violations,omitempty"`
+
+	// Metadata is any additional key/value information about the error.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// HelpURL is a link to the documentation of the error.
+	HelpURL string `json:"help_url,omitempty"`
+
+	// Items are the errors of the items of a batch operation, see Multi.
+	Items []ItemError `json:"errors,omitempty"`
+}
+
+// ItemError describes the error of an item of a batch operation, e.g. a row of a sheet.
+type ItemError struct {
+	// Index is the index of the item.
+	Index int `json:"index"`
+
+	// Type is the app error type of the error.
+	Type string `json:"type"`
+
+	// Code is the machine-readable code of the error, if any.
+	Code string `json:"code,omitempty"`
+
+	// Message is the message that the clients get, see PublicMessage.
+	Message string `json:"message"`
+}
+
+// WithCode sets the machine-readable code of the error.
+func (appErr *AppError) WithCode(code string) *AppError {
+	appErr.Details.Code = code
+	return appErr
+}
+
+// WithViolations adds the field violations to the error.
+func (appErr *AppError) WithViolations(violations ...FieldViolation) *AppError {
+	appErr.Details.Violations = append(appErr.Details.Violations, violations...)
+	return appErr
+}
+
+// WithMetadata adds the key/value information to the error.
+func (appErr *AppError) WithMetadata(key, value string) *AppError {
+	if appErr.Details.Metadata == nil {
+		appErr.Details.Metadata = map[string]string{}
+	}
+	appErr.Details.Metadata[key] = value
+	return appErr
+}
+
+// WithHelpURL sets the link to the documentation of the error.
+func (appErr *AppError) WithHelpURL(url string) *AppError {
+	appErr.Details.HelpURL = url
+	return appErr
+}
+
+// grpcDetails converts the app error type and details to the `google.rpc` error details: an
+// ErrorInfo whose domain is the type, whose reason is the code and whose metadata is the metadata
+// along with the rules of the violations, a BadRequest with the violations, a Help with the help
+// URL and an ErrorInfo per item error.
+func (appErr *AppError) grpcDetails() []protoiface.MessageV1 {
+	d := appErr.Details
+	info := &errdetails.ErrorInfo{
+		Reason:   d.Code,
+		Domain:   appErr.Type,
+		Metadata: map[string]string{},
+	}
+	for k, v := range d.Metadata {
+		info.Metadata[k] = v
+	}
+
+	details := []protoiface.MessageV1{info}
+	if len(d.Violations) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, v := range d.Violations {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Field,
+				Description: v.Message,
+			})
+			if v.Rule != "" {
+				info.Metadata[violationRulePrefix+v.Field] = v.Rule
+			}
+		}
+		details = append(details, badRequest)
+	}
+
+	if d.HelpURL != "" {
+		details = append(details, &errdetails.Help{
+			Links: []*errdetails.Help_Link{{Description: LookupType(appErr.Type).Message, Url: d.HelpURL}},
+		})
+	}
+
+	for _, item := range d.Items {
+		details = append(details, &errdetails.ErrorInfo{
+			Reason: item.Code,
+			Domain: item.Type,
+			Metadata: map[string]string{
+				itemIndexKey:   strconv.Itoa(item.Index),
+				itemMessageKey: item.Message,
+			},
+		})
+	}
+
+	return details
+}
+
+// FromGRPCStatus rebuilds the app error from the gRPC status, e.g. as returned by a service whose
+// gRPC server converts its app errors. The type, the code, the metadata, the violations and the
+// item errors are read from the `google.rpc` error details, see GRPCStatus. A status without an
+// ErrorInfo detail gets the type of its gRPC code, or UnknownError. It returns nil for an OK status.
+func FromGRPCStatus(s *status.Status) *AppError {
+	if s.Code() == codes.OK {
+		return nil
+	}
+
+	appErr := &AppError{Err: errors.New(s.Message()), Type: typeOfCode(s.Code())}
+	rules := map[string]string{}
+	hasInfo := false
+	for _, detail := range s.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if hasInfo {
+				index, _ := strconv.Atoi(d.Metadata[itemIndexKey])
+				appErr.Details.Items = append(appErr.Details.Items, ItemError{
+					Index:   index,
+					Type:    d.Domain,
+					Code:    d.Reason,
+					Message: d.Metadata[itemMessageKey],
+				})
+				continue
+			}
+			hasInfo = true
+
+			if d.Domain != "" {
+				appErr.Type = d.Domain
+			}
+			appErr.Details.Code = d.Reason
+			for k, v := range d.Metadata {
+				if field := strings.TrimPrefix(k, violationRulePrefix); field != k {
+					rules[field] = v
+					continue
+				}
+				appErr.WithMetadata(k, v)
+			}
+		case *errdetails.BadRequest:
+			for _, v := range d.FieldViolations {
+				appErr.WithViolations(FieldViolation{Field: v.Field, Message: v.Description})
+			}
+		case *errdetails.Help:
+			if len(d.Links) > 0 {
+				appErr.Details.HelpURL = d.Links[0].Url
+			}
+		}
+	}
+
+	for i, v := range appErr.Details.Violations {
+		appErr.Details.Violations[i].Rule = rules[v.Field]
+	}
+
+	return appErr
+}
+
+// HasAppErrorDetails reports whether the gRPC status was converted from an app error, i.e. it has
+// an ErrorInfo detail.
+func HasAppErrorDetails(s *status.Status) bool {
+	for _, detail := range s.Details() {
+		if _, ok := detail.(*errdetails.ErrorInfo); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// typeOfCode returns the app error type of the gRPC code: the built-in type of the code, or else
+// the first registered type of the code, sorted by type, or else UnknownError.
+func typeOfCode(code codes.Code) string {
+	switch code {
+	case codes.InvalidArgument:
+		return ValidationError
+	case codes.NotFound:
+		return NotFound
+	case codes.AlreadyExists:
+		return ResourceAlreadyExists
+	case codes.Unauthenticated:
+		return NotAuthenticated
+	case codes.PermissionDenied:
+		return NotAuthorized
+	case codes.ResourceExhausted:
+		return RateLimited
+	case codes.DeadlineExceeded:
+		return Timeout
+	case codes.Unavailable:
+		return Unavailable
+	case codes.FailedPrecondition:
+		return PreconditionFailed
+	case codes.Aborted:
+		return Conflict
+	case codes.Canceled:
+		return Canceled
+	case codes.Unimplemented:
+		return NotImplemented
+	}
+
+	for _, info := range RegisteredTypes() {
+		if info.GRPCCode == code && code != codes.Unknown && code != codes.Internal {
+			return info.Type
+		}
+	}
+
+	return UnknownError
+}
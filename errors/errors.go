@@ -1,12 +1,19 @@
 // Package errors defines the domain errors used in the application.
 package errors
 
-import "errors"
+import (
+	"context"
+	"errors"
+
+	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
 
 const (
 	// InputEmpty error indicates missing input field
 	InputEmpty        = "InputEmpty"
-	inputEmptyMessage = "inputEmpty message"
+	inputEmptyMessage = "input is empty"
 
 	// NotFound error indicates a missing / not found record
 	NotFound        = "NotFound"
@@ -26,7 +33,7 @@ const (
 
 	// NotAuthenticated indicates an authentication error
 	NotAuthenticated             = "NotAuthenticated"
-	notAuthenticatedErrorMessage = "not Authenticated"
+	notAuthenticatedErrorMessage = "not authenticated"
 
 	// TokenGeneratorError indicates an token generation error
 	TokenGeneratorError        = "TokenGeneratorError"
@@ -39,50 +46,70 @@ const (
 	// UnknownError indicates an error that the app cannot find the cause for
 	UnknownError        = "UnknownError"
 	unknownErrorMessage = "something went wrong"
+
+	// RateLimited indicates that the client has sent too many requests
+	RateLimited        = "RateLimited"
+	rateLimitedMessage = "too many requests"
+
+	// Timeout indicates that the operation didn't complete before its deadline
+	Timeout        = "Timeout"
+	timeoutMessage = "the operation timed out"
+
+	// Unavailable indicates that a dependency (e.g database, remote service) is unreachable
+	Unavailable        = "Unavailable"
+	unavailableMessage = "service unavailable"
+
+	// PreconditionFailed indicates that the system isn't in the state required by the operation
+	PreconditionFailed        = "PreconditionFailed"
+	preconditionFailedMessage = "precondition failed"
+
+	// Conflict indicates a concurrent modification of the resource, e.g. a stale version
+	Conflict        = "Conflict"
+	conflictMessage = "the resource was modified concurrently"
+
+	// PayloadTooLarge indicates that the request or the downloaded content is too large
+	PayloadTooLarge        = "PayloadTooLarge"
+	payloadTooLargeMessage = "payload too large"
+
+	// Canceled indicates that the operation was canceled, typically by the client
+	Canceled        = "Canceled"
+	canceledMessage = "the operation was canceled"
+
+	// NotImplemented indicates that the operation isn't implemented
+	NotImplemented        = "NotImplemented"
+	notImplementedMessage = "not implemented"
 )
 
 // AppError defines an application (domain) error
 type AppError struct {
 	Err  error
 	Type string
+
+	// Details are the optional structured details of the error, see WithCode, WithViolations,
+	// WithMetadata and WithHelpURL.
+	Details Details
+
+	// stack is where the error was constructed, if the stack capture is enabled, see SetStackTrace.
+	stack pkgerrors.StackTrace
 }
 
-// NewAppError initializes a new domain error using an error and its type.
+// NewAppError initializes a new domain error using an error and its type, capturing the stack
+// trace if it is enabled, see SetStackTrace.
 func NewAppError(err error, errType string) *AppError {
 	return &AppError{
-		Err:  err,
-		Type: errType,
+		Err:   err,
+		Type:  errType,
+		stack: captureStack(),
 	}
 }
 
-// NewAppErrorWithType initializes a new default error for a given type.
+// NewAppErrorWithType initializes a new default error for a given type, its message is the
+// registered message of the type, see RegisterType.
 func NewAppErrorWithType(errType string) *AppError {
-	var err error
-
-	switch errType {
-	case NotFound:
-		err = errors.New(notFoundMessage)
-	case ValidationError:
-		err = errors.New(validationErrorMessage)
-	case ResourceAlreadyExists:
-		err = errors.New(alreadyExistsErrorMessage)
-	case RepositoryError:
-		err = errors.New(repositoryErrorMessage)
-	case NotAuthenticated:
-		err = errors.New(notAuthenticatedErrorMessage)
-	case NotAuthorized:
-		err = errors.New(notAuthorizedErrorMessage)
-	case TokenGeneratorError:
-		err = errors.New(tokenGeneratorErrorMessage)
-	case InputEmpty:
-		err = errors.New(inputEmptyMessage)
-	default:
-		err = errors.New(unknownErrorMessage)
-	}
-
 	return &AppError{
-		Err:  err,
-		Type: errType,
+		Err:   errors.New(LookupType(errType).Message),
+		Type:  errType,
+		stack: captureStack(),
 	}
 }
 
@@ -91,10 +118,163 @@ func (appErr *AppError) Error() string {
 	return appErr.Err.Error()
 }
 
-// ErrorType determines the app error type
+// Unwrap returns the underlying error.
+func (appErr *AppError) Unwrap() error {
+	return appErr.Err
+}
+
+// Is reports whether the target is an app error of the same type, so that
+// `errors.Is(err, NewAppErrorWithType(NotFound))` matches any not found error.
+func (appErr *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	return ok && t.Type == appErr.Type
+}
+
+// PublicMessage returns the message that the clients get, i.e. InternalMessage if the type is
+// internal, see TypeInfo.
+func (appErr *AppError) PublicMessage() string {
+	if LookupType(appErr.Type).Internal {
+		return InternalMessage
+	}
+
+	return appErr.Error()
+}
+
+// GRPCStatus converts the app error to the gRPC status of its type, so that it is returned with
+// the registered gRPC code and the `google.rpc` error details by the gRPC servers.
+func (appErr *AppError) GRPCStatus() *status.Status {
+	return appErr.LocalizedGRPCStatus()
+}
+
+// LocalizedGRPCStatus converts the app error to the gRPC status of its type, like GRPCStatus, with
+// the message translated in the first of the preferred locales that has a translation of it, see
+// LocalizedMessage. A translated status also has a `google.rpc.LocalizedMessage` detail.
+func (appErr *AppError) LocalizedGRPCStatus(locales ...string) *status.Status {
+	message, locale := appErr.LocalizedMessage(locales...)
+	s := status.New(LookupType(appErr.Type).GRPCCode, message)
+
+	details := appErr.grpcDetails()
+	if locale != "" {
+		details = append(details, &errdetails.LocalizedMessage{Locale: locale, Message: message})
+	}
+	if withDetails, err := s.WithDetails(details...); err == nil {
+		return withDetails
+	}
+
+	return s
+}
+
+// ErrorType determines the app error type, looking through the wrapped errors.
 func ErrorType(err error) string {
-	if appErr, ok := err.(*AppError); ok {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
 		return appErr.Type
 	}
 	return UnknownError
 }
+
+// FromContextError converts the context errors, even wrapped, to app errors: a
+// context.DeadlineExceeded to a Timeout and a context.Canceled to a Canceled app error. It returns
+// nil for the other errors.
+func FromContextError(err error) *AppError {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return &AppError{Err: err, Type: Timeout, stack: captureStack()}
+	case errors.Is(err, context.Canceled):
+		return &AppError{Err: err, Type: Canceled, stack: captureStack()}
+	}
+
+	return nil
+}
+
+// IsRetryable reports whether the error is an app error of a retryable type, see TypeInfo.
+func IsRetryable(err error) bool {
+	var appErr *AppError
+	return errors.As(err, &appErr) && LookupType(appErr.Type).Retryable
+}
+
+// IsType reports whether the error, or any error that it wraps, is an app error of the type.
+func IsType(err error, errType string) bool {
+	var appErr *AppError
+	return errors.As(err, &appErr) && appErr.Type == errType
+}
+
+// IsInputEmpty reports whether the error is an InputEmpty app error.
+func IsInputEmpty(err error) bool {
+	return IsType(err, InputEmpty)
+}
+
+// IsNotFound reports whether the error is a NotFound app error.
+func IsNotFound(err error) bool {
+	return IsType(err, NotFound)
+}
+
+// IsValidationError reports whether the error is a ValidationError app error.
+func IsValidationError(err error) bool {
+	return IsType(err, ValidationError)
+}
+
+// IsResourceAlreadyExists reports whether the error is a ResourceAlreadyExists app error.
+func IsResourceAlreadyExists(err error) bool {
+	return IsType(err, ResourceAlreadyExists)
+}
+
+// IsRepositoryError reports whether the error is a RepositoryError app error.
+func IsRepositoryError(err error) bool {
+	return IsType(err, RepositoryError)
+}
+
+// IsNotAuthenticated reports whether the error is a NotAuthenticated app error.
+func IsNotAuthenticated(err error) bool {
+	return IsType(err, NotAuthenticated)
+}
+
+// IsTokenGeneratorError reports whether the error is a TokenGeneratorError app error.
+func IsTokenGeneratorError(err error) bool {
+	return IsType(err, TokenGeneratorError)
+}
+
+// IsNotAuthorized reports whether the error is a NotAuthorized app error.
+func IsNotAuthorized(err error) bool {
+	return IsType(err, NotAuthorized)
+}
+
+// IsRateLimited reports whether the error is a RateLimited app error.
+func IsRateLimited(err error) bool {
+	return IsType(err, RateLimited)
+}
+
+// IsTimeout reports whether the error is a Timeout app error.
+func IsTimeout(err error) bool {
+	return IsType(err, Timeout)
+}
+
+// IsUnavailable reports whether the error is an Unavailable app error.
+func IsUnavailable(err error) bool {
+	return IsType(err, Unavailable)
+}
+
+// IsPreconditionFailed reports whether the error is a PreconditionFailed app error.
+func IsPreconditionFailed(err error) bool {
+	return IsType(err, PreconditionFailed)
+}
+
+// IsConflict reports whether the error is a Conflict app error.
+func IsConflict(err error) bool {
+	return IsType(err, Conflict)
+}
+
+// IsPayloadTooLarge reports whether the error is a PayloadTooLarge app error.
+func IsPayloadTooLarge(err error) bool {
+	return IsType(err, PayloadTooLarge)
+}
+
+// IsCanceled reports whether the error is a Canceled app error.
+func IsCanceled(err error) bool {
+	return IsType(err, Canceled)
+}
+
+// IsNotImplemented reports whether the error is a NotImplemented app error.
+func IsNotImplemented(err error) bool {
+	return IsType(err, NotImplemented)
+}
@@ -0,0 +1,248 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"testing/fstest"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_AppError_Wrapped(t *testing.T) {
+	cause := errors.New("no rows")
+	err := fmt.Errorf("unable to read the sheet: %w", NewAppError(cause, NotFound))
+
+	assert.Equal(t, NotFound, ErrorType(err))
+	assert.True(t, IsNotFound(err))
+	assert.False(t, IsValidationError(err))
+	assert.True(t, errors.Is(err, NewAppErrorWithType(NotFound)))
+	assert.False(t, errors.Is(err, NewAppErrorWithType(RepositoryError)))
+	assert.True(t, errors.Is(err, cause))
+
+	var appErr *AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, cause, appErr.Unwrap())
+
+	assert.Equal(t, UnknownError, ErrorType(errors.New("plain")))
+	assert.Equal(t, UnknownError, ErrorType(nil))
+}
+
+func Test_RegisterType(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, LookupType(NotFound).HTTPStatus)
+	assert.Equal(t, codes.NotFound, LookupType(NotFound).GRPCCode)
+	assert.Equal(t, codes.Unknown, LookupType("Unregistered").GRPCCode)
+	assert.Equal(t, "Unregistered", LookupType("Unregistered").Type)
+
+	RegisterType(TypeInfo{
+		Type:       "QuotaExceeded",
+		Message:    "quota exceeded",
+		HTTPStatus: http.StatusTooManyRequests,
+		GRPCCode:   codes.ResourceExhausted,
+		LogLevel:   zapcore.WarnLevel,
+		Retryable:  true,
+	})
+	defer func() {
+		typesMu.Lock()
+		delete(types, "QuotaExceeded")
+		typesMu.Unlock()
+	}()
+
+	err := fmt.Errorf("checkout: %w", NewAppErrorWithType("QuotaExceeded"))
+	assert.Equal(t, "checkout: quota exceeded", err.Error())
+	assert.True(t, IsRetryable(err))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	internal := NewAppError(errors.New("pq: connection refused"), RepositoryError)
+	assert.Equal(t, InternalMessage, internal.PublicMessage())
+	assert.Equal(t, InternalMessage, internal.GRPCStatus().Message())
+}
+
+func Test_AppError_Details(t *testing.T) {
+	err := NewAppErrorWithType(ValidationError).
+		WithCode("SIGNUP_INVALID").
+		WithViolations(FieldViolation{Field: "email", Rule: "required", Message: "is required"}).
+		WithMetadata("form", "signup").
+		WithHelpURL("https://docs.example.com/errors/signup")
+
+	b, jsonErr := json.Marshal(err.Details)
+	assert.Nil(t, jsonErr)
+	assert.JSONEq(t, `{
+		"code": "SIGNUP_INVALID",
+		"violations": [{"field": "email", "rule": "required", "message": "is required"}],
+		"metadata": {"form": "signup"},
+		"help_url": "https://docs.example.com/errors/signup"
+	}`, string(b))
+
+	s := err.GRPCStatus()
+	assert.Equal(t, codes.InvalidArgument, s.Code())
+	assert.Len(t, s.Details(), 3)
+
+	info := s.Details()[0].(*errdetails.ErrorInfo)
+	assert.Equal(t, ValidationError, info.Domain)
+	assert.Equal(t, "SIGNUP_INVALID", info.Reason)
+	assert.Equal(t, map[string]string{"form": "signup", "violation_rule:email": "required"}, info.Metadata)
+
+	badRequest := s.Details()[1].(*errdetails.BadRequest)
+	assert.Equal(t, "email", badRequest.FieldViolations[0].Field)
+	assert.Equal(t, "is required", badRequest.FieldViolations[0].Description)
+
+	help := s.Details()[2].(*errdetails.Help)
+	assert.Equal(t, "https://docs.example.com/errors/signup", help.Links[0].Url)
+}
+
+func Test_AppError_Stack(t *testing.T) {
+	defer SetStackTrace(StackTraceEnabled())
+
+	SetStackTrace(false)
+	assert.Nil(t, NewAppErrorWithType(NotFound).StackTrace())
+
+	SetStackTrace(true)
+	cause := NewAppError(errors.New("pq: connection refused"), RepositoryError)
+	err := NewAppError(fmt.Errorf("unable to save the user: %w", cause), UnknownError)
+
+	assert.NotEmpty(t, err.StackTrace())
+	assert.Equal(t, "Test_AppError_Stack", fmt.Sprintf("%n", err.StackTrace()[0]))
+	assert.Equal(t, cause.StackTrace(), Stack(fmt.Errorf("handler: %w", err)))
+	assert.Nil(t, Stack(errors.New("plain")))
+
+	assert.Equal(t, "unable to save the user: pq: connection refused", fmt.Sprintf("%v", err))
+	verbose := fmt.Sprintf("%+v", err)
+	assert.Contains(t, verbose, "UnknownError: unable to save the user: pq: connection refused\n")
+	assert.Contains(t, verbose, "errors.Test_AppError_Stack\n")
+	assert.Contains(t, verbose, "caused by: RepositoryError: pq: connection refused\n")
+
+	recovered := func() (err *AppError) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = Recovered(p)
+			}
+		}()
+		panic("nil map")
+	}()
+	SetStackTrace(false)
+	assert.Equal(t, "panic: nil map", recovered.Error())
+	assert.Equal(t, UnknownError, recovered.Type)
+	assert.Contains(t, fmt.Sprintf("%+v", recovered.StackTrace()), "Test_AppError_Stack")
+}
+
+func Test_LocalizedMessage(t *testing.T) {
+	assert.Equal(t, []string{"ta-IN", "ta", "en"}, ParseAcceptLanguage("en;q=0.5, ta-IN, ta;q=0.8, *;q=0.1"))
+	assert.Empty(t, ParseAcceptLanguage(""))
+
+	assert.Nil(t, LoadMessages(fstest.MapFS{
+		"locales/ta.yaml": {Data: []byte("ValidationError: சரிபார்ப்பு பிழை\nValidationError.AGE_RANGE: \"வயது {{.min}} முதல் {{.max}} வரை இருக்க வேண்டும்\"\n")},
+		"locales/README":  {Data: []byte("ignored")},
+		"locales/mr.json": {Data: []byte(`{"NotFound": "नोंद सापडली नाही"}`)},
+	}, "locales"))
+	assert.NotNil(t, LoadMessages(fstest.MapFS{"locales/bad.yaml": {Data: []byte("NotFound: \"{{.broken\"")}}, "locales"))
+	defer func() {
+		catalogMu.Lock()
+		catalog = map[string]map[string]*template.Template{}
+		catalogMu.Unlock()
+	}()
+
+	err := NewAppErrorWithType(ValidationError).WithCode("AGE_RANGE").WithMetadata("min", "5").WithMetadata("max", "18")
+	message, locale := err.LocalizedMessage("ta-IN")
+	assert.Equal(t, "வயது 5 முதல் 18 வரை இருக்க வேண்டும்", message)
+	assert.Equal(t, "ta", locale)
+
+	message, _ = NewAppErrorWithType(ValidationError).WithCode("OTHER").LocalizedMessage("ta")
+	assert.Equal(t, "சரிபார்ப்பு பிழை", message)
+
+	message, locale = NewAppErrorWithType(NotFound).LocalizedMessage("fr", "mr")
+	assert.Equal(t, "नोंद सापडली नाही", message)
+	assert.Equal(t, "mr", locale)
+
+	message, locale = NewAppErrorWithType(NotFound).LocalizedMessage("fr")
+	assert.Equal(t, "record not found", message)
+	assert.Empty(t, locale)
+}
+
+func Test_InfrastructureTypes(t *testing.T) {
+	tests := []struct {
+		errType    string
+		httpStatus int
+		grpcCode   codes.Code
+		retryable  bool
+	}{
+		{RateLimited, http.StatusTooManyRequests, codes.ResourceExhausted, true},
+		{Timeout, http.StatusGatewayTimeout, codes.DeadlineExceeded, true},
+		{Unavailable, http.StatusServiceUnavailable, codes.Unavailable, true},
+		{PreconditionFailed, http.StatusPreconditionFailed, codes.FailedPrecondition, false},
+		{Conflict, http.StatusConflict, codes.Aborted, true},
+		{PayloadTooLarge, http.StatusRequestEntityTooLarge, codes.ResourceExhausted, false},
+		{Canceled, StatusClientClosedRequest, codes.Canceled, false},
+		{NotImplemented, http.StatusNotImplemented, codes.Unimplemented, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.errType, func(t *testing.T) {
+			err := NewAppErrorWithType(tt.errType)
+			assert.Equal(t, tt.httpStatus, LookupType(tt.errType).HTTPStatus)
+			assert.Equal(t, tt.grpcCode, status.Code(err))
+			assert.Equal(t, tt.retryable, IsRetryable(err))
+			assert.NotEqual(t, unknownErrorMessage, err.Error())
+
+			if tt.errType != PayloadTooLarge {
+				assert.Equal(t, tt.errType, FromGRPCStatus(status.New(tt.grpcCode, "")).Type)
+			}
+		})
+	}
+
+	assert.True(t, IsTimeout(FromContextError(fmt.Errorf("query: %w", context.DeadlineExceeded))))
+	assert.True(t, IsCanceled(FromContextError(context.Canceled)))
+	assert.Nil(t, FromContextError(errors.New("plain")))
+}
+
+func Test_Multi(t *testing.T) {
+	errRow := errors.New("invalid email")
+	multi := &Multi{}
+	assert.Nil(t, multi.ErrorOrNil())
+
+	multi.Add(7, NewAppErrorWithType(NotFound).WithCode("CLASS_NOT_FOUND"))
+	multi.Add(3, NewAppError(errRow, ValidationError))
+	multi.Add(5, nil)
+	multi.Add(4, NewAppErrorWithType(ValidationError))
+	multi.Add(9, errors.New("pq: connection refused"))
+
+	err := fmt.Errorf("import students: %w", multi.ErrorOrNil())
+	assert.Equal(t, "import students: 4 errors occurred: #3: invalid email; #4: validation error; #7: record not found; #9: pq: connection refused", err.Error())
+	assert.True(t, errors.Is(err, errRow))
+	assert.True(t, errors.Is(err, NewAppErrorWithType(NotFound)))
+	assert.False(t, errors.Is(err, NewAppErrorWithType(NotAuthorized)))
+
+	var appErr *AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, ValidationError, appErr.Type)
+	assert.Equal(t, map[string]int{ValidationError: 2, NotFound: 1, UnknownError: 1}, multi.Summary())
+
+	aggregated := ToAppError(err)
+	assert.Equal(t, UnknownError, aggregated.Type)
+	assert.Equal(t, "4 items failed: 1 UnknownError, 1 NotFound, 2 ValidationError", aggregated.Error())
+	assert.True(t, errors.Is(aggregated, errRow))
+	assert.Equal(t, []ItemError{
+		{Index: 3, Type: ValidationError, Message: "invalid email"},
+		{Index: 4, Type: ValidationError, Message: "validation error"},
+		{Index: 7, Type: NotFound, Code: "CLASS_NOT_FOUND", Message: "record not found"},
+		{Index: 9, Type: UnknownError, Message: InternalMessage},
+	}, aggregated.Details.Items)
+
+	rebuilt := FromGRPCStatus(aggregated.GRPCStatus())
+	assert.Equal(t, UnknownError, rebuilt.Type)
+	assert.Equal(t, aggregated.Details.Items, rebuilt.Details.Items)
+
+	same := &Multi{}
+	same.Add(1, NewAppErrorWithType(ValidationError))
+	same.Add(2, NewAppErrorWithType(ValidationError))
+	assert.Equal(t, ValidationError, same.AppError().Type)
+	assert.Equal(t, "2 items failed: 2 ValidationError", same.AppError().Error())
+}
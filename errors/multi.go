@@ -0,0 +1,176 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// IndexedError is the error of the item at Index of a batch operation, e.g. a row of a sheet.
+type IndexedError struct {
+	Index int
+	Err   error
+}
+
+// Error returns the message of the error prefixed with its index.
+func (e IndexedError) Error() string {
+	return fmt.Sprintf("#%d: %s", e.Index, e.Err.Error())
+}
+
+// Unwrap returns the underlying error.
+func (e IndexedError) Unwrap() error {
+	return e.Err
+}
+
+// Multi collects the errors of the items of a batch operation, so that it can report them all at
+// once instead of stopping at the first one. `errors.Is` and `errors.As` look through all its
+// errors, e.g. `errors.As(multi, &appErr)` finds the app error of its lowest index.
+type Multi struct {
+	// Errors are the errors of the items, sorted by index.
+	Errors []IndexedError
+}
+
+// Add adds the error of the item at the index, a nil error is ignored.
+func (m *Multi) Add(index int, err error) {
+	if err == nil {
+		return
+	}
+
+	i := sort.Search(len(m.Errors), func(i int) bool {
+		return m.Errors[i].Index > index
+	})
+	m.Errors = append(m.Errors, IndexedError{})
+	copy(m.Errors[i+1:], m.Errors[i:])
+	m.Errors[i] = IndexedError{Index: index, Err: err}
+}
+
+// Len returns the number of errors.
+func (m *Multi) Len() int {
+	return len(m.Errors)
+}
+
+// ErrorOrNil returns the multi-error if it has any error, or else nil.
+func (m *Multi) ErrorOrNil() error {
+	if m == nil || len(m.Errors) == 0 {
+		return nil
+	}
+
+	return m
+}
+
+// Error returns the messages of the errors prefixed with their index, e.g.
+// "2 errors occurred: #3: invalid email; #7: record not found".
+func (m *Multi) Error() string {
+	messages := make([]string, len(m.Errors))
+	for i, e := range m.Errors {
+		messages[i] = e.Error()
+	}
+
+	return fmt.Sprintf("%d errors occurred: %s", len(m.Errors), strings.Join(messages, "; "))
+}
+
+// Is reports whether any of the errors matches the target.
+func (m *Multi) Is(target error) bool {
+	for _, e := range m.Errors {
+		if errors.Is(e.Err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first of the errors that matches the target, and if so, sets the target to it.
+func (m *Multi) As(target interface{}) bool {
+	for _, e := range m.Errors {
+		if errors.As(e.Err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Summary returns the number of errors by app error type, the errors that aren't app errors being
+// counted as UnknownError.
+func (m *Multi) Summary() map[string]int {
+	summary := map[string]int{}
+	for _, e := range m.Errors {
+		summary[ErrorType(e.Err)]++
+	}
+
+	return summary
+}
+
+// AppError aggregates the errors into an app error, whose details list them, see ItemError. Its type
+// is the type of the errors if they all have the same, or else the type with the highest HTTP status,
+// and its message summarizes the errors by type, e.g. "3 items failed: 2 ValidationError, 1 NotFound".
+func (m *Multi) AppError() *AppError {
+	summary := m.Summary()
+	types := make([]string, 0, len(summary))
+	for errType := range summary {
+		types = append(types, errType)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		si, sj := LookupType(types[i]).HTTPStatus, LookupType(types[j]).HTTPStatus
+		return si > sj || (si == sj && types[i] < types[j])
+	})
+
+	counts := make([]string, len(types))
+	for i, errType := range types {
+		counts[i] = fmt.Sprintf("%d %s", summary[errType], errType)
+	}
+
+	appErr := &AppError{
+		Err:   &multiSummaryError{fmt.Sprintf("%d items failed: %s", len(m.Errors), strings.Join(counts, ", ")), m},
+		Type:  UnknownError,
+		stack: captureStack(),
+	}
+	if len(types) > 0 {
+		appErr.Type = types[0]
+	}
+
+	for _, e := range m.Errors {
+		item := ItemError{Index: e.Index, Type: ErrorType(e.Err)}
+		var itemErr *AppError
+		if errors.As(e.Err, &itemErr) {
+			item.Code = itemErr.Details.Code
+			item.Message = itemErr.PublicMessage()
+		} else {
+			item.Message = InternalMessage
+		}
+		appErr.Details.Items = append(appErr.Details.Items, item)
+	}
+
+	return appErr
+}
+
+// ToAppError returns the app error of the error's chain, or the aggregated app error of its
+// multi-error, whichever comes first, see Multi.AppError. It returns nil if there is none.
+func ToAppError(err error) *AppError {
+	for ; err != nil; err = errors.Unwrap(err) {
+		switch e := err.(type) {
+		case *AppError:
+			return e
+		case *Multi:
+			return e.AppError()
+		}
+	}
+
+	return nil
+}
+
+// multiSummaryError summarizes the multi-error that it wraps.
+type multiSummaryError struct {
+	summary string
+	multi   *Multi
+}
+
+func (e *multiSummaryError) Error() string {
+	return e.summary
+}
+
+func (e *multiSummaryError) Unwrap() error {
+	return e.multi
+}
@@ -0,0 +1,122 @@
+package errors
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+// InternalMessage is the message that the clients get instead of the message of an internal
+// error, see TypeInfo.
+const InternalMessage = "We are working to improve the flow of this request."
+
+// StatusClientClosedRequest is the non-standard HTTP status of the Canceled errors, as the client
+// closed the request before the response.
+const StatusClientClosedRequest = 499
+
+// TypeInfo describes how an app error type is reported.
+type TypeInfo struct {
+	// Type is the app error type, e.g. NotFound.
+	Type string
+
+	// Message is the default message of the type's errors, see NewAppErrorWithType.
+	Message string
+
+	// HTTPStatus is the HTTP status code of the type's errors.
+	HTTPStatus int
+
+	// GRPCCode is the gRPC status code of the type's errors.
+	GRPCCode codes.Code
+
+	// LogLevel is the level that the type's errors are logged at.
+	LogLevel zapcore.Level
+
+	// Retryable indicates whether the failed operation may succeed if it is retried.
+	Retryable bool
+
+	// Internal indicates whether the message of the type's errors must be hidden from the clients,
+	// who get InternalMessage instead.
+	Internal bool
+}
+
+var (
+	typesMu sync.RWMutex
+	types   = map[string]TypeInfo{}
+)
+
+func init() {
+	for _, info := range []TypeInfo{
+		{Type: InputEmpty, Message: inputEmptyMessage, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument, LogLevel: zapcore.InfoLevel},
+		{Type: NotFound, Message: notFoundMessage, HTTPStatus: http.StatusNotFound, GRPCCode: codes.NotFound, LogLevel: zapcore.InfoLevel},
+		{Type: ValidationError, Message: validationErrorMessage, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument, LogLevel: zapcore.InfoLevel},
+		{Type: ResourceAlreadyExists, Message: alreadyExistsErrorMessage, HTTPStatus: http.StatusConflict, GRPCCode: codes.AlreadyExists, LogLevel: zapcore.InfoLevel},
+		{Type: RepositoryError, Message: repositoryErrorMessage, HTTPStatus: http.StatusInternalServerError, GRPCCode: codes.Internal, LogLevel: zapcore.ErrorLevel, Retryable: true, Internal: true},
+		{Type: NotAuthenticated, Message: notAuthenticatedErrorMessage, HTTPStatus: http.StatusUnauthorized, GRPCCode: codes.Unauthenticated, LogLevel: zapcore.WarnLevel},
+		{Type: TokenGeneratorError, Message: tokenGeneratorErrorMessage, HTTPStatus: http.StatusInternalServerError, GRPCCode: codes.Internal, LogLevel: zapcore.ErrorLevel, Internal: true},
+		{Type: NotAuthorized, Message: notAuthorizedErrorMessage, HTTPStatus: http.StatusForbidden, GRPCCode: codes.PermissionDenied, LogLevel: zapcore.WarnLevel},
+		{Type: UnknownError, Message: unknownErrorMessage, HTTPStatus: http.StatusInternalServerError, GRPCCode: codes.Unknown, LogLevel: zapcore.ErrorLevel, Internal: true},
+		{Type: RateLimited, Message: rateLimitedMessage, HTTPStatus: http.StatusTooManyRequests, GRPCCode: codes.ResourceExhausted, LogLevel: zapcore.WarnLevel, Retryable: true},
+		{Type: Timeout, Message: timeoutMessage, HTTPStatus: http.StatusGatewayTimeout, GRPCCode: codes.DeadlineExceeded, LogLevel: zapcore.WarnLevel, Retryable: true, Internal: true},
+		{Type: Unavailable, Message: unavailableMessage, HTTPStatus: http.StatusServiceUnavailable, GRPCCode: codes.Unavailable, LogLevel: zapcore.ErrorLevel, Retryable: true, Internal: true},
+		{Type: PreconditionFailed, Message: preconditionFailedMessage, HTTPStatus: http.StatusPreconditionFailed, GRPCCode: codes.FailedPrecondition, LogLevel: zapcore.InfoLevel},
+		{Type: Conflict, Message: conflictMessage, HTTPStatus: http.StatusConflict, GRPCCode: codes.Aborted, LogLevel: zapcore.InfoLevel, Retryable: true},
+		{Type: PayloadTooLarge, Message: payloadTooLargeMessage, HTTPStatus: http.StatusRequestEntityTooLarge, GRPCCode: codes.ResourceExhausted, LogLevel: zapcore.InfoLevel},
+		{Type: Canceled, Message: canceledMessage, HTTPStatus: StatusClientClosedRequest, GRPCCode: codes.Canceled, LogLevel: zapcore.InfoLevel},
+		{Type: NotImplemented, Message: notImplementedMessage, HTTPStatus: http.StatusNotImplemented, GRPCCode: codes.Unimplemented, LogLevel: zapcore.WarnLevel},
+	} {
+		RegisterType(info)
+	}
+}
+
+// RegisterType registers how the app error type is reported, replacing any existing registration of
+// the type. It lets the applications define their own types, e.g.
+//
+//	errors.RegisterType(errors.TypeInfo{
+//		Type:       "QuotaExceeded",
+//		Message:    "quota exceeded",
+//		HTTPStatus: http.StatusTooManyRequests,
+//		GRPCCode:   codes.ResourceExhausted,
+//		LogLevel:   zapcore.WarnLevel,
+//		Retryable:  true,
+//	})
+func RegisterType(info TypeInfo) {
+	typesMu.Lock()
+	defer typesMu.Unlock()
+
+	types[info.Type] = info
+}
+
+// LookupType returns how the app error type is reported, the types that aren't registered are
+// reported as UnknownError.
+func LookupType(errType string) TypeInfo {
+	typesMu.RLock()
+	defer typesMu.RUnlock()
+
+	if info, ok := types[errType]; ok {
+		return info
+	}
+
+	info := types[UnknownError]
+	info.Type = errType
+
+	return info
+}
+
+// RegisteredTypes returns the registered types, sorted by type.
+func RegisteredTypes() []TypeInfo {
+	typesMu.RLock()
+	defer typesMu.RUnlock()
+
+	infos := make([]TypeInfo, 0, len(types))
+	for _, info := range types {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Type < infos[j].Type
+	})
+
+	return infos
+}
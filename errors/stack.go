@@ -0,0 +1,141 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// StackTraceEnv is the environment variable that enables, e.g. `ERRORS_STACK_TRACE=true`, or
+// disables the stack capture of the app errors. By default, it is only enabled when `APP_ENV` isn't
+// set or is development.
+const StackTraceEnv = "ERRORS_STACK_TRACE"
+
+// stackDepth is the maximum number of frames of the captured stack traces.
+const stackDepth = 32
+
+var stackTraceEnabled atomic.Bool
+
+func init() {
+	enabled := os.Getenv("APP_ENV") == "" || os.Getenv("APP_ENV") == "development"
+	if v, err := strconv.ParseBool(os.Getenv(StackTraceEnv)); err == nil {
+		enabled = v
+	}
+	stackTraceEnabled.Store(enabled)
+}
+
+// SetStackTrace enables or disables the stack capture of the app errors, overriding the
+// StackTraceEnv environment variable.
+func SetStackTrace(enabled bool) {
+	stackTraceEnabled.Store(enabled)
+}
+
+// StackTraceEnabled reports whether the app errors capture the stack trace where they are
+// constructed.
+func StackTraceEnabled() bool {
+	return stackTraceEnabled.Load()
+}
+
+// StackTrace returns the stack trace captured where the app error was constructed, or nil if the
+// stack capture is disabled. It lets the tools that understand `github.com/pkg/errors` stack traces,
+// e.g. the Sentry SDK, report the origin of the error.
+func (appErr *AppError) StackTrace() pkgerrors.StackTrace {
+	return appErr.stack
+}
+
+// Format formats the app error: `%s` and `%v` print its message, `%+v` prints its type, its
+// message and its stack trace, followed by the first error of its chain that has a stack trace of
+// its own, if any.
+func (appErr *AppError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = fmt.Fprintf(s, "%s: %s", appErr.Type, appErr.Error())
+			appErr.stack.Format(s, verb)
+			if cause := stackCause(appErr.Err); cause != nil {
+				_, _ = fmt.Fprintf(s, "\ncaused by: %+v", cause)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(s, appErr.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", appErr.Error())
+	}
+}
+
+// Stack returns the stack trace of the error's origin, i.e. the stack trace of the innermost error
+// of its chain that has one, or nil.
+func Stack(err error) pkgerrors.StackTrace {
+	var stack pkgerrors.StackTrace
+	for ; err != nil; err = errors.Unwrap(err) {
+		if st, ok := err.(interface{ StackTrace() pkgerrors.StackTrace }); ok && len(st.StackTrace()) > 0 {
+			stack = st.StackTrace()
+		}
+	}
+
+	return stack
+}
+
+// Recovered converts a value recovered from a panic to an UnknownError app error whose stack trace,
+// captured whatever SetStackTrace says, points to the panic. It must be called while the panic is
+// being recovered, i.e. by the deferred function that recovers or a function that it calls, e.g.
+//
+//	defer func() {
+//		if p := recover(); p != nil {
+//			err = errors.Recovered(p)
+//		}
+//	}()
+func Recovered(p interface{}) *AppError {
+	err, ok := p.(error)
+	if !ok {
+		err = fmt.Errorf("%v", p)
+	}
+
+	return &AppError{
+		Err:   fmt.Errorf("panic: %w", err),
+		Type:  UnknownError,
+		stack: callers(3),
+	}
+}
+
+// captureStack returns the stack trace of the caller of the app error constructor that calls it, if
+// the stack capture is enabled.
+func captureStack() pkgerrors.StackTrace {
+	if !StackTraceEnabled() {
+		return nil
+	}
+
+	// skip runtime.Callers, callers, captureStack and the constructor
+	return callers(4)
+}
+
+func callers(skip int) pkgerrors.StackTrace {
+	var pcs [stackDepth]uintptr
+	n := runtime.Callers(skip, pcs[:])
+
+	stack := make(pkgerrors.StackTrace, n)
+	for i, pc := range pcs[:n] {
+		stack[i] = pkgerrors.Frame(pc)
+	}
+
+	return stack
+}
+
+// stackCause returns the first error of the chain that has a stack trace.
+func stackCause(err error) error {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if st, ok := err.(interface{ StackTrace() pkgerrors.StackTrace }); ok && len(st.StackTrace()) > 0 {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -68,7 +68,7 @@ func main() {
 		PrometheusEnabled: true,
 		NewRelicOptions: struct {
 			ServiceName string
-			LicenseKey  string
+			LicenseKey  string `secret:"true"`
 		}{
 			ServiceName: "ServiceName",
 			LicenseKey:  "NewRelicLicenseKey",
@@ -7,7 +7,8 @@ import (
 	"github.com/Raj63/go-sdk/excel"
 )
 
-// ReadRow implements excel.Excel
+// ReadRow implements excel.Excel, the failures of exec don't stop the reading of the other rows,
+// they are returned as an *errors.Multi indexed by the row number, 1 being the header row.
 func (e *excelize) ReadRow(inputSheetName string, exec func(row excel.DataRow) error) error {
 	f, closer, err := e.GetFile()
 	if err != nil {
@@ -24,6 +25,7 @@ func (e *excelize) ReadRow(inputSheetName string, exec func(row excel.DataRow) e
 			}
 
 			headerMaps := make(map[int]string, 0)
+			rowErrs := &errors.Multi{}
 			for rowIndex, row := range rows {
 				dataRow := excel.DataRow{
 					Data: make([]excel.KeyValue, 0),
@@ -40,10 +42,10 @@ func (e *excelize) ReadRow(inputSheetName string, exec func(row excel.DataRow) e
 				}
 				// invoke the execute function
 				if err := exec(dataRow); err != nil {
-					return fmt.Errorf("failed to execute func: %w", err)
+					rowErrs.Add(rowIndex+1, fmt.Errorf("failed to execute func: %w", err))
 				}
 			}
-			return nil
+			return rowErrs.ErrorOrNil()
 		}
 	}
 	return errors.NewAppErrorWithType(errors.NotFound)
@@ -0,0 +1,53 @@
+package featureflags
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+type contextKey struct{}
+
+type subjectKey struct{}
+
+// NewContext returns a copy of the context that holds the evaluated flags.
+func NewContext(ctx context.Context, e Evaluation) context.Context {
+	return context.WithValue(ctx, contextKey{}, e)
+}
+
+// FromContext returns the evaluated flags that the context holds, e.g. as put by GinMiddleware or
+// UnaryServerInterceptor. It returns an empty Evaluation if there are none.
+func FromContext(ctx context.Context) Evaluation {
+	if c, ok := ctx.(*gin.Context); ok && c.Request != nil {
+		ctx = c.Request.Context()
+	}
+
+	if e, ok := ctx.Value(contextKey{}).(Evaluation); ok {
+		return e
+	}
+
+	return Evaluation{}
+}
+
+// IsEnabled returns whether the flag is on in the evaluated flags that the context holds.
+func IsEnabled(ctx context.Context, name string) bool {
+	return FromContext(ctx).Enabled(name)
+}
+
+// WithSubject returns a copy of the context that holds the authenticated subject, which the
+// authentication middlewares set so that the flags are evaluated for it, see DefaultGinSubject and
+// DefaultGRPCSubject.
+func WithSubject(ctx context.Context, s Subject) context.Context {
+	return context.WithValue(ctx, subjectKey{}, s)
+}
+
+// SubjectFromContext returns the subject that the context holds, see WithSubject. It returns an
+// empty Subject if there is none.
+func SubjectFromContext(ctx context.Context) Subject {
+	if c, ok := ctx.(*gin.Context); ok && c.Request != nil {
+		ctx = c.Request.Context()
+	}
+
+	s, _ := ctx.Value(subjectKey{}).(Subject)
+	return s
+}
@@ -0,0 +1,283 @@
+// Package featureflags evaluates the feature flags that are defined in the config files, e.g.
+//
+//	feature_flags:
+//	  dark_mode: true
+//	  new_checkout:
+//	    rollout: 25
+//	    by: tenant
+//	    allow: [tenant-1, tenant-2]
+//	    deny: [tenant-3]
+//
+// and loaded with the config package, see Config.
+package featureflags
+
+import (
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/Raj63/go-sdk/config"
+)
+
+const (
+	// ByUser rolls a flag out by the user ID.
+	ByUser = "user"
+
+	// ByTenant rolls a flag out by the tenant ID.
+	ByTenant = "tenant"
+)
+
+func init() {
+	config.RegisterParser(reflect.TypeOf(Flags{}), parseFlags)
+}
+
+// Config indicates the feature flags, it is meant to be embedded in the application config and
+// loaded with config.ParseAppConfig or a config.Loader.
+type Config struct {
+	// Flags are the feature flag definitions keyed by the flag name. In an environment variable,
+	// they are written as `name:spec` pairs joined with `,`, the spec being either `true`, `false`
+	// or the escaped `key:value` pairs of a Flag, e.g. `dark_mode:true,new_checkout:rollout\:25`.
+	Flags Flags `env:"FEATURE_FLAGS"`
+}
+
+// Flags are the feature flag definitions keyed by the flag name.
+type Flags map[string]Flag
+
+// Flag defines a feature flag. An enabled flag is on for the IDs of the allow list, off for the
+// IDs of the deny list and otherwise on for the Rollout percentage of the user or tenant IDs,
+// while a disabled flag is off for everyone.
+type Flag struct {
+	// Enabled is the flag's kill switch. By default, it is true.
+	Enabled bool
+
+	// Rollout is the percentage of the user or tenant IDs that the flag is on for, between 0 and
+	// 100. The IDs are bucketed with a stable hash of the flag name and the ID, so each ID keeps its
+	// result as the percentage grows. By default, it is 100.
+	Rollout float64
+
+	// By indicates whether the rollout is keyed by the user ID (ByUser) or the tenant ID
+	// (ByTenant). By default, it is ByUser.
+	By string
+
+	// Allow is the list of the user or tenant IDs that the flag is always on for.
+	Allow []string
+
+	// Deny is the list of the user or tenant IDs that the flag is always off for, it takes
+	// precedence over Allow.
+	Deny []string
+}
+
+// Subject is who the flags are evaluated for.
+type Subject struct {
+	// UserID is the ID of the user, if any.
+	UserID string
+
+	// TenantID is the ID of the tenant, if any.
+	TenantID string
+}
+
+// Evaluation holds the evaluated flags keyed by the flag name.
+type Evaluation map[string]bool
+
+// Enabled returns whether the flag is on, an unknown flag is off.
+func (e Evaluation) Enabled(name string) bool {
+	return e[name]
+}
+
+// FeatureFlags evaluates the feature flags, its definitions can be updated and its flags can be
+// overridden at runtime.
+type FeatureFlags struct {
+	mu        sync.RWMutex
+	flags     Flags
+	overrides map[string]bool
+}
+
+// New initialises the feature flags with the config.
+func New(c *Config) *FeatureFlags {
+	f := &FeatureFlags{overrides: map[string]bool{}}
+	f.Update(c.Flags)
+
+	return f
+}
+
+// Update replaces the flag definitions, e.g. from a config.Reloader subscriber. The runtime
+// overrides are kept.
+func (f *FeatureFlags) Update(flags Flags) {
+	copied := make(Flags, len(flags))
+	for name, flag := range flags {
+		copied[name] = flag
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.flags = copied
+}
+
+// Override forces the flag on or off for everyone until the override is cleared.
+func (f *FeatureFlags) Override(name string, enabled bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.overrides[name] = enabled
+}
+
+// ClearOverride removes the flag's runtime override.
+func (f *FeatureFlags) ClearOverride(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.overrides, name)
+}
+
+// Names returns the names of the defined and the overridden flags, sorted.
+func (f *FeatureFlags) Names() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	names := make([]string, 0, len(f.flags)+len(f.overrides))
+	for name := range f.flags {
+		names = append(names, name)
+	}
+	for name := range f.overrides {
+		if _, ok := f.flags[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Enabled returns whether the flag is on for the subject, an unknown flag is off.
+func (f *FeatureFlags) Enabled(name string, s Subject) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.enabled(name, s)
+}
+
+// Evaluate evaluates every flag for the subject.
+func (f *FeatureFlags) Evaluate(s Subject) Evaluation {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	e := make(Evaluation, len(f.flags)+len(f.overrides))
+	for name := range f.flags {
+		e[name] = f.enabled(name, s)
+	}
+	for name, enabled := range f.overrides {
+		e[name] = enabled
+	}
+
+	return e
+}
+
+func (f *FeatureFlags) enabled(name string, s Subject) bool {
+	if enabled, ok := f.overrides[name]; ok {
+		return enabled
+	}
+
+	flag, ok := f.flags[name]
+	if !ok || !flag.Enabled {
+		return false
+	}
+
+	if matches(flag.Deny, s) {
+		return false
+	}
+
+	if matches(flag.Allow, s) {
+		return true
+	}
+
+	if flag.Rollout >= 100 {
+		return true
+	}
+
+	id := s.UserID
+	if flag.By == ByTenant {
+		id = s.TenantID
+	}
+
+	if flag.Rollout <= 0 || id == "" {
+		return false
+	}
+
+	return bucket(name, id) < flag.Rollout
+}
+
+// bucket hashes the flag name and the ID into a percentage between 0 and 100.
+func bucket(name, id string) float64 {
+	h := fnv.New32a()
+	h.Write([]byte(name + ":" + id))
+
+	return float64(h.Sum32()%10000) / 100
+}
+
+func matches(ids []string, s Subject) bool {
+	for _, id := range ids {
+		if id != "" && (id == s.UserID || id == s.TenantID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseFlags(v string) (interface{}, error) {
+	specs, err := config.ParseMap(v)
+	if err != nil {
+		return nil, err
+	}
+
+	flags := Flags{}
+	for name, spec := range specs {
+		flag, err := parseFlag(spec)
+		if err != nil {
+			return nil, fmt.Errorf("feature flag %q: %w", name, err)
+		}
+		flags[name] = flag
+	}
+
+	return flags, nil
+}
+
+func parseFlag(spec string) (Flag, error) {
+	flag := Flag{Enabled: true, Rollout: 100, By: ByUser}
+	if enabled, err := strconv.ParseBool(spec); err == nil {
+		flag.Enabled = enabled
+		return flag, nil
+	}
+
+	attrs, err := config.ParseMap(spec)
+	if err != nil {
+		return Flag{}, err
+	}
+
+	for k, v := range attrs {
+		switch k {
+		case "enabled":
+			if flag.Enabled, err = strconv.ParseBool(v); err != nil {
+				return Flag{}, fmt.Errorf("invalid enabled %q", v)
+			}
+		case "rollout":
+			if flag.Rollout, err = strconv.ParseFloat(v, 64); err != nil || flag.Rollout < 0 || flag.Rollout > 100 {
+				return Flag{}, fmt.Errorf("invalid rollout %q, expected a percentage between 0 and 100", v)
+			}
+		case "by":
+			if v != ByUser && v != ByTenant {
+				return Flag{}, fmt.Errorf("invalid by %q, expected %q or %q", v, ByUser, ByTenant)
+			}
+			flag.By = v
+		case "allow":
+			flag.Allow = config.ParseList(v)
+		case "deny":
+			flag.Deny = config.ParseList(v)
+		default:
+			return Flag{}, fmt.Errorf("unknown attribute %q", k)
+		}
+	}
+
+	return flag, nil
+}
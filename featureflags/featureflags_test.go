@@ -0,0 +1,147 @@
+package featureflags
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/Raj63/go-sdk/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type testAppConfig struct {
+	FeatureFlags Config
+}
+
+func loadTestFlags(t *testing.T) *FeatureFlags {
+	c := testAppConfig{}
+	_, err := config.NewLoader(&config.LoaderConfig{
+		Env: "production",
+		FS: fstest.MapFS{
+			"configs/production.yaml": {Data: []byte(`
+feature_flags:
+  dark_mode: true
+  legacy_export: false
+  new_checkout:
+    rollout: 50
+    by: tenant
+    allow: [tenant-1]
+    deny: [tenant-2, user-9]
+  killed:
+    enabled: false
+    allow: [tenant-1]
+`)},
+		},
+	}).Load(&c)
+	assert.Nil(t, err)
+
+	return New(&c.FeatureFlags)
+}
+
+func Test_FeatureFlags(t *testing.T) {
+	f := loadTestFlags(t)
+
+	assert.Equal(t, []string{"dark_mode", "killed", "legacy_export", "new_checkout"}, f.Names())
+	assert.True(t, f.Enabled("dark_mode", Subject{}))
+	assert.False(t, f.Enabled("legacy_export", Subject{}))
+	assert.False(t, f.Enabled("unknown", Subject{}))
+	assert.False(t, f.Enabled("killed", Subject{TenantID: "tenant-1"}))
+	assert.True(t, f.Enabled("new_checkout", Subject{TenantID: "tenant-1"}))
+	assert.False(t, f.Enabled("new_checkout", Subject{TenantID: "tenant-2"}))
+	assert.False(t, f.Enabled("new_checkout", Subject{UserID: "user-9", TenantID: "tenant-1"}))
+	assert.False(t, f.Enabled("new_checkout", Subject{UserID: "user-1"}))
+
+	t.Run("should roll out to a stable share of the tenants", func(t *testing.T) {
+		enabled := 0
+		for i := 0; i < 1000; i++ {
+			s := Subject{TenantID: fmt.Sprintf("tenant-%d", i+100)}
+			if f.Enabled("new_checkout", s) {
+				enabled++
+			}
+			assert.Equal(t, f.Enabled("new_checkout", s), f.Enabled("new_checkout", s))
+		}
+		assert.InDelta(t, 500, enabled, 60)
+	})
+
+	t.Run("should apply the runtime overrides", func(t *testing.T) {
+		f.Override("legacy_export", true)
+		f.Override("beta", true)
+		assert.True(t, f.Enabled("legacy_export", Subject{}))
+		assert.Equal(t, Evaluation{
+			"beta":          true,
+			"dark_mode":     true,
+			"killed":        false,
+			"legacy_export": true,
+			"new_checkout":  true,
+		}, f.Evaluate(Subject{TenantID: "tenant-1"}))
+
+		f.ClearOverride("legacy_export")
+		assert.False(t, f.Enabled("legacy_export", Subject{}))
+	})
+}
+
+func Test_parseFlags(t *testing.T) {
+	flags, err := parseFlags(`dark_mode:true,beta:rollout\:10\,allow\:u1|u2`)
+	assert.Nil(t, err)
+	assert.Equal(t, Flags{
+		"dark_mode": {Enabled: true, Rollout: 100, By: ByUser},
+		"beta":      {Enabled: true, Rollout: 10, By: ByUser, Allow: []string{"u1", "u2"}},
+	}, flags)
+
+	_, err = parseFlags(`beta:rollout\:150`)
+	assert.EqualError(t, err, `feature flag "beta": invalid rollout "150", expected a percentage between 0 and 100`)
+}
+
+func Test_GinMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	// the authentication middleware sets the subject of the token
+	router.Use(func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "Bearer tenant-1-token" {
+			c.Request = c.Request.WithContext(WithSubject(c.Request.Context(), Subject{TenantID: "tenant-1"}))
+		}
+	})
+	router.Use(GinMiddleware(loadTestFlags(t), nil))
+	router.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, "%v", IsEnabled(c, "new_checkout"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer tenant-1-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "true", w.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Tenant-ID", "tenant-1")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "false", w.Body.String())
+}
+
+func Test_UnaryServerInterceptor(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant-id", "tenant-1"))
+	interceptor := UnaryServerInterceptor(loadTestFlags(t), nil)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return FromContext(ctx), nil
+	}
+
+	resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+
+	assert.Nil(t, err)
+	assert.False(t, resp.(Evaluation).Enabled("new_checkout"))
+	assert.True(t, resp.(Evaluation).Enabled("dark_mode"))
+
+	resp, err = interceptor(WithSubject(ctx, Subject{TenantID: "tenant-1"}), nil, &grpc.UnaryServerInfo{}, handler)
+
+	assert.Nil(t, err)
+	assert.True(t, resp.(Evaluation).Enabled("new_checkout"))
+}
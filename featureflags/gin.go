@@ -0,0 +1,25 @@
+package featureflags
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultGinSubject returns the subject that the authentication middlewares put into the request
+// context, see WithSubject. The client-supplied headers, e.g. `X-User-ID`, aren't trusted.
+func DefaultGinSubject(c *gin.Context) Subject {
+	return SubjectFromContext(c.Request.Context())
+}
+
+// GinMiddleware evaluates the flags for the subject of every request and puts them into the
+// request context, see FromContext, so it must be placed after the authentication middlewares. By
+// default, the subject is returned by DefaultGinSubject.
+func GinMiddleware(f *FeatureFlags, subject func(c *gin.Context) Subject) gin.HandlerFunc {
+	if subject == nil {
+		subject = DefaultGinSubject
+	}
+
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(NewContext(c.Request.Context(), f.Evaluate(subject(c))))
+		c.Next()
+	}
+}
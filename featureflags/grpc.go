@@ -0,0 +1,43 @@
+package featureflags
+
+import (
+	"context"
+
+	grpcmdw "github.com/grpc-ecosystem/go-grpc-middleware"
+	"google.golang.org/grpc"
+)
+
+// DefaultGRPCSubject returns the subject that the authentication interceptors put into the call
+// context, see WithSubject. The client-supplied metadata, e.g. `x-user-id`, isn't trusted.
+func DefaultGRPCSubject(ctx context.Context) Subject {
+	return SubjectFromContext(ctx)
+}
+
+// UnaryServerInterceptor evaluates the flags for the subject of every unary call and puts them
+// into the call context, see FromContext, so it must be placed after the authentication
+// interceptors. By default, the subject is returned by DefaultGRPCSubject.
+func UnaryServerInterceptor(f *FeatureFlags, subject func(ctx context.Context) Subject) grpc.UnaryServerInterceptor {
+	if subject == nil {
+		subject = DefaultGRPCSubject
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(NewContext(ctx, f.Evaluate(subject(ctx))), req)
+	}
+}
+
+// StreamServerInterceptor evaluates the flags for the subject of every stream and puts them into
+// the stream context, see FromContext, so it must be placed after the authentication interceptors.
+// By default, the subject is returned by DefaultGRPCSubject.
+func StreamServerInterceptor(f *FeatureFlags, subject func(ctx context.Context) Subject) grpc.StreamServerInterceptor {
+	if subject == nil {
+		subject = DefaultGRPCSubject
+	}
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		wrapped := grpcmdw.WrapServerStream(ss)
+		wrapped.WrappedContext = NewContext(ss.Context(), f.Evaluate(subject(ss.Context())))
+
+		return handler(srv, wrapped)
+	}
+}
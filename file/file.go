@@ -1,8 +1,14 @@
 package file
 
 import (
+	"bytes"
+	"context"
+	stderrors "errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,21 +16,99 @@ import (
 	"github.com/Raj63/go-sdk/errors"
 )
 
+// MaxDownloadSize is the maximum size of the resources downloaded from http, the larger resources
+// fail with a PayloadTooLarge app error.
+var MaxDownloadSize int64 = 32 << 20
+
 // Download is a helper function to download resources for the specified path from http or local
 func Download(path string) (io.Reader, error) {
+	return DownloadContext(context.Background(), path)
+}
+
+// DownloadContext is a helper function to download resources for the specified path from http or
+// local, the http requests are bound to the context. The failures are app errors: NotFound for a
+// missing resource, Timeout or Canceled when the context is done, Unavailable when the server can't
+// be reached or fails, RateLimited when it limits the requests and PayloadTooLarge when the resource
+// is larger than MaxDownloadSize.
+func DownloadContext(ctx context.Context, path string) (io.Reader, error) {
 	if path == "" {
 		return nil, errors.NewAppErrorWithType(errors.InputEmpty)
 	}
 
-	var reader io.Reader
-	var err error
-	if strings.HasPrefix(path, "http") || strings.HasPrefix(path, "https") {
-		// TODO: download the file and return it in the image format
-	} else {
-		reader, err = os.Open(filepath.Clean(path))
-		if err != nil {
-			return nil, fmt.Errorf("could not open local file(%s), error=%v", path, err)
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return downloadHTTP(ctx, path)
+	}
+
+	reader, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		if stderrors.Is(err, fs.ErrNotExist) {
+			return nil, errors.NewAppError(fmt.Errorf("could not find local file(%s): %w", path, err), errors.NotFound)
 		}
+		return nil, fmt.Errorf("could not open local file(%s), error=%w", path, err)
 	}
+
 	return reader, nil
 }
+
+func downloadHTTP(ctx context.Context, url string) (io.Reader, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.NewAppError(fmt.Errorf("invalid url(%s): %w", url, err), errors.ValidationError)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		err = fmt.Errorf("could not download file(%s): %w", url, err)
+		if appErr := errors.FromContextError(err); appErr != nil {
+			return nil, appErr
+		}
+
+		var netErr net.Error
+		if stderrors.As(err, &netErr) && netErr.Timeout() {
+			return nil, errors.NewAppError(err, errors.Timeout)
+		}
+		return nil, errors.NewAppError(err, errors.Unavailable)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, errors.NewAppError(fmt.Errorf("could not download file(%s), status=%s", url, resp.Status), statusErrorType(resp.StatusCode))
+	}
+
+	if resp.ContentLength > MaxDownloadSize {
+		return nil, errors.NewAppError(fmt.Errorf("file(%s) is larger than %d bytes", url, MaxDownloadSize), errors.PayloadTooLarge)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, MaxDownloadSize+1))
+	if err != nil {
+		if appErr := errors.FromContextError(err); appErr != nil {
+			return nil, appErr
+		}
+		return nil, errors.NewAppError(fmt.Errorf("could not read file(%s): %w", url, err), errors.Unavailable)
+	}
+	if int64(len(b)) > MaxDownloadSize {
+		return nil, errors.NewAppError(fmt.Errorf("file(%s) is larger than %d bytes", url, MaxDownloadSize), errors.PayloadTooLarge)
+	}
+
+	return bytes.NewReader(b), nil
+}
+
+// statusErrorType returns the app error type of a failed download's HTTP status.
+func statusErrorType(code int) string {
+	switch {
+	case code == http.StatusNotFound || code == http.StatusGone:
+		return errors.NotFound
+	case code == http.StatusUnauthorized:
+		return errors.NotAuthenticated
+	case code == http.StatusForbidden:
+		return errors.NotAuthorized
+	case code == http.StatusTooManyRequests:
+		return errors.RateLimited
+	case code == http.StatusRequestTimeout || code == http.StatusGatewayTimeout:
+		return errors.Timeout
+	case code >= 500:
+		return errors.Unavailable
+	}
+
+	return errors.UnknownError
+}
@@ -0,0 +1,62 @@
+package file
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Raj63/go-sdk/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/logo.png":
+			_, _ = w.Write([]byte("png"))
+		case "/limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/down":
+			w.WriteHeader(http.StatusBadGateway)
+		case "/slow":
+			time.Sleep(100 * time.Millisecond)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	reader, err := DownloadContext(context.Background(), srv.URL+"/logo.png")
+	assert.Nil(t, err)
+	b, _ := io.ReadAll(reader)
+	assert.Equal(t, "png", string(b))
+
+	tests := []struct {
+		path    string
+		errType string
+	}{
+		{srv.URL + "/missing", errors.NotFound},
+		{srv.URL + "/limited", errors.RateLimited},
+		{srv.URL + "/down", errors.Unavailable},
+		{"testdata/missing.png", errors.NotFound},
+		{"", errors.InputEmpty},
+	}
+	for _, tt := range tests {
+		_, err := DownloadContext(context.Background(), tt.path)
+		assert.Equal(t, tt.errType, errors.ErrorType(err), tt.path)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = DownloadContext(ctx, srv.URL+"/slow")
+	assert.True(t, errors.IsTimeout(err))
+
+	maxDownloadSize := MaxDownloadSize
+	defer func() { MaxDownloadSize = maxDownloadSize }()
+	MaxDownloadSize = 2
+	_, err = DownloadContext(context.Background(), srv.URL+"/logo.png")
+	assert.True(t, errors.IsPayloadTooLarge(err))
+}
@@ -1,7 +1,6 @@
 package grpc
 
 import (
-	"context"
 	"time"
 
 	"github.com/Raj63/go-sdk/logger"
@@ -51,7 +50,9 @@ type ClientConfig struct {
 	}
 }
 
-// NewClient initialises a GRPC client.
+// NewClient initialises a GRPC client. The errors that the server converted from app errors are
+// rebuilt as *errors.AppError, so that their type is kept across services. The payloads are logged
+// with their sensitive values masked, see logger.Redactor.
 func NewClient(cfg *ClientConfig, logger *logger.Logger, tracerProvider *tracer.Provider) (*Client, error) {
 	defaultClientConfig(cfg)
 
@@ -73,12 +74,8 @@ func NewClient(cfg *ClientConfig, logger *logger.Logger, tracerProvider *tracer.
 						logger.Desugar(),
 						grpczap.WithMessageProducer(loggingInterceptor),
 					),
-					grpczap.PayloadUnaryClientInterceptor(
-						logger.Desugar(),
-						func(ctx context.Context, fullMethodName string) bool {
-							return true
-						},
-					),
+					payloadUnaryClientInterceptor(logger),
+					errorsUnaryClientInterceptor,
 				),
 			),
 		),
@@ -92,12 +89,8 @@ func NewClient(cfg *ClientConfig, logger *logger.Logger, tracerProvider *tracer.
 						logger.Desugar(),
 						grpczap.WithMessageProducer(loggingInterceptor),
 					),
-					grpczap.PayloadStreamClientInterceptor(
-						logger.Desugar(),
-						func(ctx context.Context, fullMethodName string) bool {
-							return true
-						},
-					),
+					payloadStreamClientInterceptor(logger),
+					errorsStreamClientInterceptor,
 				),
 			),
 		),
@@ -0,0 +1,122 @@
+package grpc
+
+import (
+	"context"
+
+	domainErrors "github.com/Raj63/go-sdk/errors"
+	"github.com/Raj63/go-sdk/logger"
+
+	grpcrecovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// appStatusError reports a wrapped app error with the gRPC status of its type, while keeping the
+// whole error chain for the logs.
+type appStatusError struct {
+	err     error
+	appErr  *domainErrors.AppError
+	locales []string
+}
+
+func (e *appStatusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *appStatusError) Unwrap() error {
+	return e.err
+}
+
+func (e *appStatusError) GRPCStatus() *status.Status {
+	return e.appErr.LocalizedGRPCStatus(e.locales...)
+}
+
+// toStatusError converts the app errors, even wrapped or aggregated by a domainErrors.Multi, to the
+// gRPC status registered for their type, see domainErrors.RegisterType, with their message
+// translated in the locales of the request, see requestLocales. The context errors are converted
+// to Timeout and Canceled app errors.
+func toStatusError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	appErr := domainErrors.ToAppError(err)
+	if appErr == nil {
+		if appErr = domainErrors.FromContextError(err); appErr == nil {
+			return err
+		}
+	}
+
+	return &appStatusError{err, appErr, requestLocales(ctx)}
+}
+
+// requestLocales returns the locales preferred by the client, from the `accept-language` metadata
+// or, for the requests proxied by `grpc-gateway`, the `Accept-Language` header.
+func requestLocales(ctx context.Context) []string {
+	var locales []string
+	for _, key := range []string{"accept-language", "grpcgateway-accept-language"} {
+		for _, v := range GetHeaderFromContext(ctx, key) {
+			locales = append(locales, domainErrors.ParseAcceptLanguage(v)...)
+		}
+	}
+
+	return locales
+}
+
+func errorsUnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	return resp, toStatusError(ctx, err)
+}
+
+func errorsStreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return toStatusError(ss.Context(), handler(srv, ss))
+}
+
+// recoveryHandler logs the recovered panics along with their stack trace and returns them as
+// UnknownError app errors, so that the clients get codes.Unknown without the panic's message.
+func recoveryHandler(logger *logger.Logger) grpcrecovery.RecoveryHandlerFuncContext {
+	return func(ctx context.Context, p interface{}) error {
+		appErr := domainErrors.Recovered(p)
+		logger.WithError(appErr).ErrorContext(ctx, "recovered from a panic")
+
+		return appErr
+	}
+}
+
+// fromStatusError rebuilds the app error from the status error of a call, as long as the status
+// was converted from an app error by the server. The other errors, e.g. codes.Unavailable, are
+// returned as-is.
+func fromStatusError(err error) error {
+	s, ok := status.FromError(err)
+	if err == nil || !ok || !domainErrors.HasAppErrorDetails(s) {
+		return err
+	}
+
+	return domainErrors.FromGRPCStatus(s)
+}
+
+func errorsUnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return fromStatusError(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+func errorsStreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	cs, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, fromStatusError(err)
+	}
+
+	return &errorsClientStream{cs}, nil
+}
+
+// errorsClientStream rebuilds the app errors of the stream messages.
+type errorsClientStream struct {
+	grpc.ClientStream
+}
+
+func (s *errorsClientStream) SendMsg(m interface{}) error {
+	return fromStatusError(s.ClientStream.SendMsg(m))
+}
+
+func (s *errorsClientStream) RecvMsg(m interface{}) error {
+	return fromStatusError(s.ClientStream.RecvMsg(m))
+}
@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	domainErrors "github.com/Raj63/go-sdk/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func Test_errorsUnaryServerInterceptor(t *testing.T) {
+	call := func(err error) error {
+		_, err = errorsUnaryServerInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, err
+		})
+		return err
+	}
+
+	err := call(fmt.Errorf("get user: %w", domainErrors.NewAppErrorWithType(domainErrors.NotFound)))
+	s := status.Convert(err)
+	assert.Equal(t, codes.NotFound, s.Code())
+	assert.Equal(t, "record not found", s.Message())
+	assert.True(t, domainErrors.IsNotFound(err))
+
+	err = call(fmt.Errorf("get user: %w", domainErrors.NewAppError(errors.New("pq: connection refused"), domainErrors.RepositoryError)))
+	s = status.Convert(err)
+	assert.Equal(t, codes.Internal, s.Code())
+	assert.Equal(t, domainErrors.InternalMessage, s.Message())
+
+	assert.Nil(t, call(nil))
+	assert.Equal(t, codes.Unknown, status.Code(call(errors.New("boom"))))
+
+	assert.Nil(t, domainErrors.RegisterMessages("hi", map[string]string{"NotFound": "रिकॉर्ड नहीं मिला"}))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("accept-language", "hi-IN,en;q=0.8"))
+	err = toStatusError(ctx, domainErrors.NewAppErrorWithType(domainErrors.NotFound))
+	s = status.Convert(err)
+	assert.Equal(t, "रिकॉर्ड नहीं मिला", s.Message())
+	localized := s.Details()[1].(*errdetails.LocalizedMessage)
+	assert.Equal(t, "hi", localized.Locale)
+	assert.Equal(t, "रिकॉर्ड नहीं मिला", localized.Message)
+}
+
+func Test_errorsUnaryClientInterceptor(t *testing.T) {
+	call := func(serverErr error) error {
+		return errorsUnaryClientInterceptor(context.Background(), "/svc/Method", nil, nil, nil, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			// the status goes over the wire as a proto
+			return status.FromProto(status.Convert(toStatusError(ctx, serverErr)).Proto()).Err()
+		})
+	}
+
+	err := call(domainErrors.NewAppErrorWithType(domainErrors.ValidationError).
+		WithCode("SIGNUP_INVALID").
+		WithMetadata("form", "signup").
+		WithViolations(domainErrors.FieldViolation{Field: "email", Rule: "required", Message: "is required"}))
+
+	var appErr *domainErrors.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, domainErrors.ValidationError, appErr.Type)
+	assert.Equal(t, "validation error", appErr.Error())
+	assert.Equal(t, domainErrors.Details{
+		Code:       "SIGNUP_INVALID",
+		Violations: []domainErrors.FieldViolation{{Field: "email", Rule: "required", Message: "is required"}},
+		Metadata:   map[string]string{"form": "signup"},
+	}, appErr.Details)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	assert.True(t, domainErrors.IsNotFound(call(fmt.Errorf("get user: %w", domainErrors.NewAppErrorWithType(domainErrors.NotFound)))))
+
+	err = call(status.Error(codes.Unavailable, "connection refused"))
+	assert.False(t, errors.As(err, &appErr))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
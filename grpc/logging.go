@@ -0,0 +1,56 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/Raj63/go-sdk/logger"
+
+	"github.com/google/uuid"
+	grpcmdw "github.com/grpc-ecosystem/go-grpc-middleware"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIDHeader is the metadata key of the request ID, which is generated if the request has
+// none.
+const RequestIDHeader = "x-request-id"
+
+// contextLogger puts the logger into the context along with the `request_id` and `route` fields,
+// see logger.FromContext, and adds them to the gRPC request logs. The request ID is read from the
+// `x-request-id` metadata, or generated, and is sent back in the same header. The `user_id` and
+// `tenant_id` fields are added by the authentication interceptors, with logger.WithContext.
+func contextLogger(ctx context.Context, l *logger.Logger, fullMethod string) context.Context {
+	requestID := ""
+	if v := GetHeaderFromContext(ctx, RequestIDHeader); len(v) > 0 {
+		requestID = v[0]
+	}
+	if requestID == "" {
+		requestID = uuid.NewString()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, requestID))
+
+	fields := []zap.Field{
+		zap.String(logger.RequestIDKey, requestID),
+		zap.String(logger.RouteKey, fullMethod),
+	}
+
+	ctxzap.AddFields(ctx, fields...)
+	return logger.WithContext(logger.NewContext(ctx, l), fields...)
+}
+
+func contextLoggerUnaryServerInterceptor(l *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(contextLogger(ctx, l, info.FullMethod), req)
+	}
+}
+
+func contextLoggerStreamServerInterceptor(l *logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		wrapped := grpcmdw.WrapServerStream(ss)
+		wrapped.WrappedContext = contextLogger(ss.Context(), l, info.FullMethod)
+
+		return handler(srv, wrapped)
+	}
+}
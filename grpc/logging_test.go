@@ -0,0 +1,31 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Raj63/go-sdk/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func Test_contextLoggerUnaryServerInterceptor(t *testing.T) {
+	log, buf, writer := logger.NewTestLogger()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-7", "x-user-id", "u-42", "x-tenant-id", "school-1"))
+
+	_, err := contextLoggerUnaryServerInterceptor(log)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/students.v1.Students/Enroll"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		logger.FromContext(ctx).Info("enrolled")
+		// the authentication interceptors add the verified user
+		logger.FromContext(logger.WithContext(ctx, zap.String(logger.UserIDKey, "u-7"))).Info("authenticated")
+		return nil, nil
+	})
+	assert.Nil(t, err)
+	_ = writer.Flush()
+
+	assert.Contains(t, buf.String(), `"request_id": "req-7", "route": "/students.v1.Students/Enroll"}`)
+	assert.Contains(t, buf.String(), `"request_id": "req-7", "route": "/students.v1.Students/Enroll", "user_id": "u-7"}`)
+	assert.NotContains(t, buf.String(), "u-42")
+	assert.NotContains(t, buf.String(), "school-1")
+}
@@ -0,0 +1,159 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domainErrors "github.com/Raj63/go-sdk/errors"
+	"github.com/Raj63/go-sdk/logger"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// LogLevelServiceName is the full name of the log level service, see
+// `api/gosdk/logger/v1/log_level.proto`.
+const LogLevelServiceName = "gosdk.logger.v1.LogLevelService"
+
+// logLevelServer reads and changes the logging levels at runtime.
+type logLevelServer struct {
+	levels *logger.Levels
+}
+
+// RegisterLogLevelService registers the log level service of the levels on the gRPC server, see
+// `api/gosdk/logger/v1/log_level.proto`.
+func RegisterLogLevelService(srv grpc.ServiceRegistrar, levels *logger.Levels) {
+	srv.RegisterService(&logLevelServiceDesc, &logLevelServer{levels})
+}
+
+func (s *logLevelServer) GetLevels(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	return levelsStruct(s.levels.Info())
+}
+
+func (s *logLevelServer) SetLevel(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	fields := req.GetFields()
+	level := fields["level"].GetStringValue()
+	if level == "" {
+		return nil, domainErrors.NewAppError(errors.New("the level is required"), domainErrors.ValidationError)
+	}
+
+	var revertAfter time.Duration
+	if v := fields["revert_after"].GetStringValue(); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, domainErrors.NewAppError(err, domainErrors.ValidationError)
+		}
+		revertAfter = d
+	}
+
+	if err := s.levels.Update(fields["name"].GetStringValue(), level, revertAfter); err != nil {
+		return nil, domainErrors.NewAppError(err, domainErrors.ValidationError)
+	}
+
+	return levelsStruct(s.levels.Info())
+}
+
+func levelsStruct(info logger.LevelsInfo) (*structpb.Struct, error) {
+	overrides := map[string]interface{}{}
+	for name, level := range info.Overrides {
+		overrides[name] = level
+	}
+
+	return structpb.NewStruct(map[string]interface{}{
+		"level":     info.Level,
+		"overrides": overrides,
+	})
+}
+
+// LogLevelClient is the client of the log level service.
+type LogLevelClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewLogLevelClient initialises the client of the log level service of the connection's server.
+func NewLogLevelClient(cc grpc.ClientConnInterface) *LogLevelClient {
+	return &LogLevelClient{cc}
+}
+
+// GetLevels returns the logging levels of the server.
+func (c *LogLevelClient) GetLevels(ctx context.Context, opts ...grpc.CallOption) (logger.LevelsInfo, error) {
+	resp := &structpb.Struct{}
+	if err := c.cc.Invoke(ctx, "/"+LogLevelServiceName+"/GetLevels", &structpb.Struct{}, resp, opts...); err != nil {
+		return logger.LevelsInfo{}, err
+	}
+
+	return levelsInfo(resp), nil
+}
+
+// SetLevel sets the logging level of the logger name of the server, or its base level if the name
+// is empty, see logger.Levels.Update, and returns its logging levels.
+func (c *LogLevelClient) SetLevel(ctx context.Context, name, level string, revertAfter time.Duration, opts ...grpc.CallOption) (logger.LevelsInfo, error) {
+	req, err := structpb.NewStruct(map[string]interface{}{
+		"name":         name,
+		"level":        level,
+		"revert_after": revertAfter.String(),
+	})
+	if err != nil {
+		return logger.LevelsInfo{}, err
+	}
+
+	resp := &structpb.Struct{}
+	if err := c.cc.Invoke(ctx, "/"+LogLevelServiceName+"/SetLevel", req, resp, opts...); err != nil {
+		return logger.LevelsInfo{}, err
+	}
+
+	return levelsInfo(resp), nil
+}
+
+func levelsInfo(s *structpb.Struct) logger.LevelsInfo {
+	info := logger.LevelsInfo{Level: s.GetFields()["level"].GetStringValue()}
+	for name, level := range s.GetFields()["overrides"].GetStructValue().GetFields() {
+		if info.Overrides == nil {
+			info.Overrides = map[string]string{}
+		}
+		info.Overrides[name] = level.GetStringValue()
+	}
+
+	return info
+}
+
+var logLevelServiceDesc = grpc.ServiceDesc{
+	ServiceName: LogLevelServiceName,
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLevels",
+			Handler:    logLevelHandler("GetLevels", (*logLevelServer).GetLevels),
+		},
+		{
+			MethodName: "SetLevel",
+			Handler:    logLevelHandler("SetLevel", (*logLevelServer).SetLevel),
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "gosdk/logger/v1/log_level.proto",
+}
+
+// logLevelHandler adapts the method of the log level server to a gRPC method handler, as generated
+// by protoc-gen-go-grpc.
+func logLevelHandler(name string, method func(*logLevelServer, context.Context, *structpb.Struct) (*structpb.Struct, error)) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		in := &structpb.Struct{}
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		if interceptor == nil {
+			return method(srv.(*logLevelServer), ctx, in)
+		}
+
+		info := &grpc.UnaryServerInfo{
+			Server:     srv,
+			FullMethod: "/" + LogLevelServiceName + "/" + name,
+		}
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return method(srv.(*logLevelServer), ctx, req.(*structpb.Struct))
+		}
+		return interceptor(ctx, in, info, handler)
+	}
+}
@@ -0,0 +1,50 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Raj63/go-sdk/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func Test_LogLevelService(t *testing.T) {
+	log, _, _ := logger.NewTestLogger()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(errorsUnaryServerInterceptor))
+	RegisterLogLevelService(srv, log.Levels())
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	client := NewLogLevelClient(conn)
+	info, err := client.GetLevels(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, logger.LevelsInfo{Level: "debug"}, info)
+
+	info, err = client.SetLevel(context.Background(), "sql", "warn", time.Minute)
+	assert.Nil(t, err)
+	assert.Equal(t, logger.LevelsInfo{Level: "debug", Overrides: map[string]string{"sql": "warn"}}, info)
+	assert.Equal(t, zapcore.WarnLevel, log.Levels().Level("sql"))
+
+	_, err = client.SetLevel(context.Background(), "", "loud", 0)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.SetLevel(context.Background(), "", "", 0)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, zapcore.DebugLevel, log.Levels().Level(""))
+}
@@ -0,0 +1,157 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+
+	"github.com/Raj63/go-sdk/logger"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	requestContent  = "grpc.request.content"
+	responseContent = "grpc.response.content"
+)
+
+// payloadUnaryServerInterceptor logs the request and response payloads with the logger of the
+// context, so it must be placed after the grpczap interceptor. The sensitive values are masked by
+// the logger's redactor, see logger.Redactor.
+func payloadUnaryServerInterceptor(l *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		entry := ctxzap.Extract(ctx)
+		logPayload(entry, l.Redactor(), req, requestContent, "server request payload logged as grpc.request.content field")
+		resp, err := handler(ctx, req)
+		if err == nil {
+			logPayload(entry, l.Redactor(), resp, responseContent, "server response payload logged as grpc.response.content field")
+		}
+
+		return resp, err
+	}
+}
+
+func payloadStreamServerInterceptor(l *logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &payloadServerStream{
+			ServerStream: ss,
+			entry:        ctxzap.Extract(ss.Context()),
+			redactor:     l.Redactor(),
+		})
+	}
+}
+
+func payloadUnaryClientInterceptor(l *logger.Logger) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		entry := clientPayloadLogger(ctx, l, method)
+		logPayload(entry, l.Redactor(), req, requestContent, "client request payload logged as grpc.request.content")
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err == nil {
+			logPayload(entry, l.Redactor(), reply, responseContent, "client response payload logged as grpc.response.content")
+		}
+
+		return err
+	}
+}
+
+func payloadStreamClientInterceptor(l *logger.Logger) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payloadClientStream{
+			ClientStream: cs,
+			entry:        clientPayloadLogger(ctx, l, method),
+			redactor:     l.Redactor(),
+		}, nil
+	}
+}
+
+type payloadServerStream struct {
+	grpc.ServerStream
+	entry    *zap.Logger
+	redactor *logger.Redactor
+}
+
+func (s *payloadServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		logPayload(s.entry, s.redactor, m, responseContent, "server response payload logged as grpc.response.content field")
+	}
+
+	return err
+}
+
+func (s *payloadServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		logPayload(s.entry, s.redactor, m, requestContent, "server request payload logged as grpc.request.content field")
+	}
+
+	return err
+}
+
+type payloadClientStream struct {
+	grpc.ClientStream
+	entry    *zap.Logger
+	redactor *logger.Redactor
+}
+
+func (s *payloadClientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		logPayload(s.entry, s.redactor, m, requestContent, "client request payload logged as grpc.request.content")
+	}
+
+	return err
+}
+
+func (s *payloadClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == nil {
+		logPayload(s.entry, s.redactor, m, responseContent, "client response payload logged as grpc.response.content")
+	}
+
+	return err
+}
+
+// clientPayloadLogger returns the logger of the client payloads with the gRPC method fields and the
+// fields of the context, see logger.Fields.
+func clientPayloadLogger(ctx context.Context, l *logger.Logger, method string) *zap.Logger {
+	fields := append([]zap.Field{
+		zap.String("grpc.service", path.Dir(method)[1:]),
+		zap.String("grpc.method", path.Base(method)),
+		zap.String("span.kind", "client"),
+		zap.String("system", "grpc"),
+	}, logger.Fields(ctx)...)
+
+	return l.Desugar().With(fields...)
+}
+
+// logPayload logs the redacted proto message as JSON, the message being marshalled only if the
+// entry is enabled.
+func logPayload(entry *zap.Logger, redactor *logger.Redactor, m interface{}, key, msg string) {
+	pb, ok := m.(proto.Message)
+	if !ok {
+		return
+	}
+
+	ce := entry.Check(zapcore.InfoLevel, msg)
+	if ce == nil {
+		return
+	}
+
+	b, err := protojson.Marshal(redactor.Proto(pb))
+	if err != nil {
+		ce.Write(zap.String(key, redactor.String(err.Error())))
+		return
+	}
+	ce.Write(zap.Reflect(key, json.RawMessage(b)))
+}
@@ -0,0 +1,29 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Raj63/go-sdk/logger"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func Test_payloadUnaryClientInterceptor(t *testing.T) {
+	log, buf, writer := logger.NewTestLogger()
+	req, _ := structpb.NewStruct(map[string]interface{}{"name": "jane", "password": "s3cret", "email": "jane@example.com"})
+	reply := &structpb.Struct{}
+
+	err := payloadUnaryClientInterceptor(log)(context.Background(), "/users.v1.Users/SignUp", req, reply, nil, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	})
+	assert.Nil(t, err)
+	_ = writer.Flush()
+
+	assert.Contains(t, buf.String(), `"grpc.service": "users.v1.Users", "grpc.method": "SignUp"`)
+	assert.Contains(t, buf.String(), `"name":"jane"`)
+	assert.NotContains(t, buf.String(), "s3cret")
+	assert.NotContains(t, buf.String(), "jane@example.com")
+	assert.Equal(t, "s3cret", req.Fields["password"].GetStringValue())
+}
@@ -5,6 +5,7 @@ import (
 	"net"
 	"time"
 
+	domainErrors "github.com/Raj63/go-sdk/errors"
 	"github.com/Raj63/go-sdk/logger"
 	"github.com/Raj63/go-sdk/tracer"
 
@@ -84,6 +85,10 @@ type ServerConfig struct {
 	// ReflectionService indicates if the gRPC server should register the reflection service.
 	ReflectionService bool
 
+	// LogLevelService indicates if the gRPC server should register the log level service that reads
+	// and changes the logging levels at runtime, see RegisterLogLevelService.
+	LogLevelService bool
+
 	// TracerProvider is the provider that uses the exporter to push traces to the collector.
 	TracerProvider *tracer.Provider
 
@@ -96,11 +101,14 @@ var (
 	system = "ok" // ok string represents the health of the system
 )
 
-// NewServer initialises a gRPC server.
+// NewServer initialises a gRPC server. The request and response payloads are logged with their
+// sensitive values masked, see logger.Redactor.
 func NewServer(c *ServerConfig, logger *logger.Logger, preStartCallback func() error, interceptors ...grpc.UnaryServerInterceptor) (*Server, error) {
 	defaultServerConfig(c)
 	defaultInterceptors := []grpc.UnaryServerInterceptor{
-		grpcrecovery.UnaryServerInterceptor(),
+		grpcrecovery.UnaryServerInterceptor(
+			grpcrecovery.WithRecoveryHandlerContext(recoveryHandler(logger)),
+		),
 		otelgrpc.UnaryServerInterceptor(
 			otelgrpc.WithTracerProvider(c.TracerProvider),
 		),
@@ -108,12 +116,9 @@ func NewServer(c *ServerConfig, logger *logger.Logger, preStartCallback func() e
 			logger.Desugar(),
 			grpczap.WithMessageProducer(loggingInterceptor),
 		),
-		grpczap.PayloadUnaryServerInterceptor(
-			logger.Desugar(),
-			func(ctx context.Context, fullMethodName string, servingObject interface{}) bool {
-				return true
-			},
-		),
+		contextLoggerUnaryServerInterceptor(logger),
+		payloadUnaryServerInterceptor(logger),
+		errorsUnaryServerInterceptor,
 	}
 	interceptors = append(defaultInterceptors, interceptors...)
 	srv := grpc.NewServer(
@@ -136,7 +141,9 @@ func NewServer(c *ServerConfig, logger *logger.Logger, preStartCallback func() e
 		),
 		grpc.StreamInterceptor(
 			grpcmdw.ChainStreamServer(
-				grpcrecovery.StreamServerInterceptor(),
+				grpcrecovery.StreamServerInterceptor(
+					grpcrecovery.WithRecoveryHandlerContext(recoveryHandler(logger)),
+				),
 				otelgrpc.StreamServerInterceptor(
 					otelgrpc.WithTracerProvider(c.TracerProvider),
 				),
@@ -144,12 +151,9 @@ func NewServer(c *ServerConfig, logger *logger.Logger, preStartCallback func() e
 					logger.Desugar(),
 					grpczap.WithMessageProducer(loggingInterceptor),
 				),
-				grpczap.PayloadStreamServerInterceptor(
-					logger.Desugar(),
-					func(ctx context.Context, fullMethodName string, servingObject interface{}) bool {
-						return true
-					},
-				),
+				contextLoggerStreamServerInterceptor(logger),
+				payloadStreamServerInterceptor(logger),
+				errorsStreamServerInterceptor,
 			),
 		),
 	)
@@ -162,6 +166,10 @@ func NewServer(c *ServerConfig, logger *logger.Logger, preStartCallback func() e
 		reflection.Register(srv)
 	}
 
+	if c.LogLevelService && logger.Levels() != nil {
+		RegisterLogLevelService(srv, logger.Levels())
+	}
+
 	return &Server{
 		c,
 		nil,
@@ -260,11 +268,16 @@ func defaultServerConfig(c *ServerConfig) {
 }
 
 func loggingInterceptor(ctx context.Context, msg string, level zapcore.Level, code codes.Code, err error, duration zapcore.Field) {
+	if appErr := domainErrors.ToAppError(err); appErr != nil {
+		level = domainErrors.LookupType(appErr.Type).LogLevel
+	}
+
 	if ce := ctxzap.Extract(ctx).Check(level, msg); ce != nil {
 		ce.Write(
 			zap.Error(err),
 			zap.String("grpc.code", code.String()),
 			zap.String("trace_id", tracer.GetTraceIDFromContext(ctx)),
+			logger.ErrorStack(err),
 			duration,
 		)
 	}
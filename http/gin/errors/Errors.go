@@ -2,52 +2,183 @@
 package errors
 
 import (
-	"net/http"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
 
 	domainErrors "github.com/Raj63/go-sdk/errors"
+	"github.com/Raj63/go-sdk/tracer"
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 )
 
-// MessagesResponse is a struct that contains the response body for the message
+// ProblemContentType is the content type of the RFC 7807 problem details responses.
+const ProblemContentType = "application/problem+json"
+
+// MessagesResponse is a struct that contains the response body for the message.
+//
+// Deprecated: the errors are responded with Problem.
 type MessagesResponse struct {
 	Message string `json:"message"`
+
+	// Details are the structured details of the error, if any.
+	domainErrors.Details
+}
+
+// Problem is the RFC 7807 problem details response body, the structured details of the error are
+// its extension members.
+type Problem struct {
+	// Type is the URI that identifies the problem type, "about:blank" by default.
+	Type string `json:"type"`
+
+	// Title is the short summary of the problem type.
+	Title string `json:"title"`
+
+	// Status is the HTTP status code.
+	Status int `json:"status"`
+
+	// Detail is the explanation of this occurrence of the problem.
+	Detail string `json:"detail,omitempty"`
+
+	// Instance is the URI reference of this occurrence of the problem, i.e. the request path.
+	Instance string `json:"instance,omitempty"`
+
+	// TraceID is the ID of the request trace, if any.
+	TraceID string `json:"trace_id,omitempty"`
+
+	// Details are the structured details of the error, if any.
+	domainErrors.Details
+}
+
+// HandlerConfig indicates how the error handler should respond to the errors.
+type HandlerConfig struct {
+	// TypeBaseURL is the base URL of the problem types, the type of an app error being
+	// `<TypeBaseURL>/<app error type>`. By default, the problem type is "about:blank".
+	TypeBaseURL string
+
+	// Convert converts the errors that aren't app errors, e.g. to map a library's errors, or
+	// returns nil to use the default conversion: the context errors become Timeout and Canceled app
+	// errors, the gin binding errors become ValidationError app errors and the other errors become
+	// UnknownError app errors.
+	Convert func(err error) *domainErrors.AppError
 }
 
-// Handler is Gin middleware to handle errors.
+// Handler is Gin middleware to handle errors with the default HandlerConfig, see NewHandler.
 func Handler(c *gin.Context) {
-	// Execute request handlers and then handle any errors
-	c.Next()
-	errs := c.Errors
-
-	if len(errs) > 0 {
-		err, ok := errs[0].Err.(*domainErrors.AppError)
-		if ok {
-			resp := MessagesResponse{Message: err.Error()}
-			switch err.Type {
-			case domainErrors.NotFound:
-				c.JSON(http.StatusNotFound, resp)
-				return
-			case domainErrors.ValidationError:
-				c.JSON(http.StatusBadRequest, resp)
-				return
-			case domainErrors.ResourceAlreadyExists:
-				c.JSON(http.StatusConflict, resp)
-				return
-			case domainErrors.NotAuthenticated:
-				c.JSON(http.StatusUnauthorized, resp)
-				return
-			case domainErrors.NotAuthorized:
-				c.JSON(http.StatusForbidden, resp)
-				return
-			case domainErrors.RepositoryError:
-				c.JSON(http.StatusInternalServerError, MessagesResponse{Message: "We are working to improve the flow of this request."})
-				return
-			default:
-				c.JSON(http.StatusInternalServerError, MessagesResponse{Message: "We are working to improve the flow of this request."})
-				return
-			}
-		}
-
-		return
+	defaultHandler(c)
+}
+
+var defaultHandler = NewHandler(&HandlerConfig{})
+
+// NewHandler returns a Gin middleware that responds to the errors of the request handlers with
+// an RFC 7807 `application/problem+json` body. The first app error of the request, including the
+// aggregated app error of a domainErrors.Multi, or else its first error, is responded with the
+// HTTP status registered for its type, see domainErrors.RegisterType, and with its detail
+// translated in the locales of the `Accept-Language` header, see domainErrors.RegisterMessages.
+// Nothing is written when the handlers have already written a response body.
+func NewHandler(config *HandlerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Defer the headers of the handlers that only set the status, e.g. c.AbortWithError of
+		// c.BindJSON, so that the problem can still be responded
+		c.Writer = &deferredHeaderWriter{c.Writer}
+
+		// Execute request handlers and then handle any errors
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Size() > 0 {
+			return
+		}
+
+		appErr := toAppError(c.Errors, config.Convert)
+		info := domainErrors.LookupType(appErr.Type)
+
+		problemType := "about:blank"
+		if config.TypeBaseURL != "" {
+			problemType = strings.TrimSuffix(config.TypeBaseURL, "/") + "/" + appErr.Type
+		}
+
+		traceID := ""
+		if sc := tracer.SpanFromContext(c.Request.Context()).SpanContext(); sc.HasTraceID() {
+			traceID = sc.TraceID().String()
+		}
+
+		detail, locale := appErr.LocalizedMessage(domainErrors.ParseAcceptLanguage(c.GetHeader("Accept-Language"))...)
+		if locale != "" {
+			c.Header("Content-Language", locale)
+		}
+
+		// gin.Context.JSON keeps the content type if it is already set
+		c.Header("Content-Type", ProblemContentType)
+		c.JSON(info.HTTPStatus, Problem{
+			Type:     problemType,
+			Title:    info.Message,
+			Status:   info.HTTPStatus,
+			Detail:   detail,
+			Instance: c.Request.URL.Path,
+			TraceID:  traceID,
+			Details:  appErr.Details,
+		})
+	}
+}
+
+// deferredHeaderWriter doesn't write the headers until the body is written, Gin writing them at the
+// end of the request otherwise.
+type deferredHeaderWriter struct {
+	gin.ResponseWriter
+}
+
+func (w *deferredHeaderWriter) WriteHeaderNow() {}
+
+func toAppError(errs []*gin.Error, convert func(err error) *domainErrors.AppError) *domainErrors.AppError {
+	for _, e := range errs {
+		if appErr := domainErrors.ToAppError(e.Err); appErr != nil {
+			return appErr
+		}
+	}
+
+	first := errs[0]
+	if convert != nil {
+		if appErr := convert(first.Err); appErr != nil {
+			return appErr
+		}
+	}
+
+	if appErr := domainErrors.FromContextError(first.Err); appErr != nil {
+		return appErr
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(first.Err, &validationErrs) {
+		appErr := domainErrors.NewAppErrorWithType(domainErrors.ValidationError)
+		for _, fe := range validationErrs {
+			appErr.WithViolations(domainErrors.FieldViolation{
+				Field:   fe.Field(),
+				Rule:    fe.Tag(),
+				Message: fmt.Sprintf("failed on the '%s' rule", fe.Tag()),
+			})
+		}
+		return appErr
 	}
+
+	if errors.Is(first.Err, io.EOF) {
+		return domainErrors.NewAppError(errors.New("the request body is empty"), domainErrors.ValidationError)
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if first.IsType(gin.ErrorTypeBind) || errors.As(first.Err, &syntaxErr) || errors.As(first.Err, &typeErr) {
+		appErr := domainErrors.NewAppError(first.Err, domainErrors.ValidationError)
+		if typeErr != nil && typeErr.Field != "" {
+			appErr.WithViolations(domainErrors.FieldViolation{
+				Field:   typeErr.Field,
+				Rule:    "type",
+				Message: "must be a " + typeErr.Type.String(),
+			})
+		}
+		return appErr
+	}
+
+	return domainErrors.NewAppError(first.Err, domainErrors.UnknownError)
 }
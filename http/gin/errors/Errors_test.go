@@ -0,0 +1,203 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	domainErrors "github.com/Raj63/go-sdk/errors"
+	"github.com/Raj63/go-sdk/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(t *testing.T, handler gin.HandlerFunc, route gin.HandlerFunc, body string, headers ...string) (*httptest.ResponseRecorder, Problem) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(handler)
+	router.POST("/users", route)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	router.ServeHTTP(w, req)
+
+	problem := Problem{}
+	if w.Header().Get("Content-Type") == ProblemContentType {
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	}
+
+	return w, problem
+}
+
+func Test_Handler(t *testing.T) {
+	t.Run("should respond the app error as a problem", func(t *testing.T) {
+		w, problem := serve(t, NewHandler(&HandlerConfig{TypeBaseURL: "https://errors.example.com/"}), func(c *gin.Context) {
+			_ = c.Error(fmt.Errorf("get user: %w", domainErrors.NewAppErrorWithType(domainErrors.NotFound).WithCode("USER_NOT_FOUND")))
+		}, "")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+		assert.Equal(t, Problem{
+			Type:     "https://errors.example.com/NotFound",
+			Title:    "record not found",
+			Status:   http.StatusNotFound,
+			Detail:   "record not found",
+			Instance: "/users",
+			Details:  domainErrors.Details{Code: "USER_NOT_FOUND"},
+		}, problem)
+	})
+
+	t.Run("should respond the other errors as internal errors", func(t *testing.T) {
+		w, problem := serve(t, Handler, func(c *gin.Context) {
+			_ = c.Error(errors.New("pq: connection refused"))
+		}, "")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "about:blank", problem.Type)
+		assert.Equal(t, domainErrors.InternalMessage, problem.Detail)
+	})
+
+	t.Run("should respond the multi-errors as a list", func(t *testing.T) {
+		w, problem := serve(t, Handler, func(c *gin.Context) {
+			rowErrs := &domainErrors.Multi{}
+			rowErrs.Add(2, domainErrors.NewAppErrorWithType(domainErrors.ValidationError).WithCode("INVALID_EMAIL"))
+			rowErrs.Add(5, domainErrors.NewAppErrorWithType(domainErrors.ValidationError))
+			_ = c.Error(fmt.Errorf("import students: %w", rowErrs))
+		}, "")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "2 items failed: 2 ValidationError", problem.Detail)
+		assert.Equal(t, []domainErrors.ItemError{
+			{Index: 2, Type: domainErrors.ValidationError, Code: "INVALID_EMAIL", Message: "validation error"},
+			{Index: 5, Type: domainErrors.ValidationError, Message: "validation error"},
+		}, problem.Items)
+	})
+
+	t.Run("should respond the context errors as timeouts", func(t *testing.T) {
+		w, problem := serve(t, Handler, func(c *gin.Context) {
+			_ = c.Error(fmt.Errorf("list users: %w", context.DeadlineExceeded))
+		}, "")
+
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+		assert.Equal(t, domainErrors.InternalMessage, problem.Detail)
+	})
+
+	t.Run("should respond the binding errors as validation errors", func(t *testing.T) {
+		w, problem := serve(t, Handler, func(c *gin.Context) {
+			var req struct {
+				Email string `json:"email" binding:"required"`
+				Age   int    `json:"age"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				_ = c.Error(err)
+			}
+		}, `{"age": 30}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []domainErrors.FieldViolation{{Field: "Email", Rule: "required", Message: "failed on the 'required' rule"}}, problem.Violations)
+
+		w, problem = serve(t, Handler, func(c *gin.Context) {
+			var req struct {
+				Age int `json:"age"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				_ = c.Error(err)
+			}
+		}, `{"age": "thirty"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "age", problem.Violations[0].Field)
+
+		w, problem = serve(t, Handler, func(c *gin.Context) {
+			var req struct {
+				Email string `json:"email" binding:"required"`
+			}
+			_ = c.BindJSON(&req)
+		}, `{}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+		assert.Equal(t, []domainErrors.FieldViolation{{Field: "Email", Rule: "required", Message: "failed on the 'required' rule"}}, problem.Violations)
+	})
+
+	t.Run("should use the custom conversion", func(t *testing.T) {
+		errQuota := errors.New("quota exceeded")
+		w, _ := serve(t, NewHandler(&HandlerConfig{
+			Convert: func(err error) *domainErrors.AppError {
+				if errors.Is(err, errQuota) {
+					return domainErrors.NewAppError(err, domainErrors.NotAuthorized)
+				}
+				return nil
+			},
+		}), func(c *gin.Context) {
+			_ = c.Error(errQuota)
+		}, "")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("should translate the detail in the accepted language", func(t *testing.T) {
+		assert.Nil(t, domainErrors.RegisterMessages("hi", map[string]string{
+			"NotFound.USER_NOT_FOUND":       "उपयोगकर्ता {{.user_id}} नहीं मिला",
+			domainErrors.InternalMessageKey: "हम इस अनुरोध को ठीक करने पर काम कर रहे हैं।",
+		}))
+
+		w, problem := serve(t, Handler, func(c *gin.Context) {
+			_ = c.Error(domainErrors.NewAppErrorWithType(domainErrors.NotFound).WithCode("USER_NOT_FOUND").WithMetadata("user_id", "42"))
+		}, "", "Accept-Language", "fr;q=0.5, hi-IN")
+
+		assert.Equal(t, "hi", w.Header().Get("Content-Language"))
+		assert.Equal(t, "उपयोगकर्ता 42 नहीं मिला", problem.Detail)
+
+		_, problem = serve(t, Handler, func(c *gin.Context) {
+			_ = c.Error(errors.New("pq: connection refused"))
+		}, "", "Accept-Language", "hi")
+
+		assert.Equal(t, "हम इस अनुरोध को ठीक करने पर काम कर रहे हैं।", problem.Detail)
+
+		w, problem = serve(t, Handler, func(c *gin.Context) {
+			_ = c.Error(domainErrors.NewAppErrorWithType(domainErrors.NotFound))
+		}, "", "Accept-Language", "fr")
+
+		assert.Empty(t, w.Header().Get("Content-Language"))
+		assert.Equal(t, "record not found", problem.Detail)
+	})
+
+	t.Run("should keep the response already written", func(t *testing.T) {
+		w, _ := serve(t, Handler, func(c *gin.Context) {
+			c.String(http.StatusAccepted, "ok")
+			_ = c.Error(errors.New("late"))
+		}, "")
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, "ok", w.Body.String())
+	})
+}
+
+func Test_Recovery(t *testing.T) {
+	log, buffer, writer := logger.NewTestLogger()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Handler, Recovery(log))
+	router.GET("/panic", func(c *gin.Context) {
+		panic("nil map")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	_ = writer.Flush()
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+	assert.NotContains(t, w.Body.String(), "nil map")
+	assert.Contains(t, buffer.String(), "panic: nil map")
+	assert.Contains(t, buffer.String(), "error_stack")
+}
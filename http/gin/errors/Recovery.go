@@ -0,0 +1,26 @@
+package errors
+
+import (
+	domainErrors "github.com/Raj63/go-sdk/errors"
+	"github.com/Raj63/go-sdk/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery returns a Gin middleware that recovers from the panics of the request handlers, logs
+// them along with their stack trace and adds them to the request errors as UnknownError app
+// errors. It must be used after the error handler, see NewHandler, which responds to them.
+func Recovery(logger *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if p := recover(); p != nil {
+				appErr := domainErrors.Recovered(p)
+				logger.WithError(appErr).ErrorContext(c.Request.Context(), "recovered from a panic")
+
+				_ = c.Error(appErr)
+				c.Abort()
+			}
+		}()
+
+		c.Next()
+	}
+}
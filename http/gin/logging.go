@@ -0,0 +1,106 @@
+package gin
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/Raj63/go-sdk/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// RequestIDHeader is the header of the request ID, which is generated if the request has none.
+const RequestIDHeader = "X-Request-ID"
+
+// LoggerMiddleware returns a Gin middleware that puts the logger into the request context along
+// with the `request_id` and `route` fields, see logger.FromContext. The request ID is read from the
+// `X-Request-ID` header, or generated, and is sent back in the same header. The `user_id` and
+// `tenant_id` fields are added by the authentication middlewares once the request is
+// authenticated, with logger.WithContext.
+func LoggerMiddleware(l *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		fields := []zap.Field{
+			zap.String(logger.RequestIDKey, requestID),
+			zap.String(logger.RouteKey, c.FullPath()),
+		}
+
+		ctx := logger.NewContext(c.Request.Context(), l)
+		c.Request = c.Request.WithContext(logger.WithContext(ctx, fields...))
+		c.Next()
+	}
+}
+
+// DefaultBodyLogMaxSize is the default maximum size of the logged request and response bodies.
+const DefaultBodyLogMaxSize = 4 << 10
+
+// BodyLoggerMiddleware returns a Gin middleware that logs the request and response bodies at the
+// debug level with the logger of the request context, see LoggerMiddleware, so it must be placed
+// after it. The bodies are truncated to maxSize bytes, DefaultBodyLogMaxSize if it isn't positive,
+// and their sensitive values are masked by the logger's redactor, see logger.Redactor.
+func BodyLoggerMiddleware(l *logger.Logger, maxSize int) gin.HandlerFunc {
+	if maxSize <= 0 {
+		maxSize = DefaultBodyLogMaxSize
+	}
+
+	return func(c *gin.Context) {
+		if !l.Desugar().Core().Enabled(zap.DebugLevel) {
+			c.Next()
+			return
+		}
+
+		entry := logger.FromContext(c.Request.Context()).Desugar()
+		if c.Request.Body != nil {
+			body, err := io.ReadAll(io.LimitReader(c.Request.Body, int64(maxSize)))
+			if err == nil && len(body) > 0 {
+				entry.Debug("http request body logged as http.request.content field",
+					zap.String("http.request.content", string(l.Redactor().JSON(body))))
+			}
+			// the handlers read the whole body, i.e. the logged part and the remaining part
+			c.Request.Body = readCloser{io.MultiReader(bytes.NewReader(body), c.Request.Body), c.Request.Body}
+		}
+
+		writer := &bodyWriter{ResponseWriter: c.Writer, maxSize: maxSize}
+		c.Writer = writer
+		c.Next()
+
+		if writer.body.Len() > 0 {
+			entry.Debug("http response body logged as http.response.content field",
+				zap.String("http.response.content", string(l.Redactor().JSON(writer.body.Bytes()))))
+		}
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// bodyWriter keeps the first maxSize bytes of the response body.
+type bodyWriter struct {
+	gin.ResponseWriter
+	body    bytes.Buffer
+	maxSize int
+}
+
+func (w *bodyWriter) Write(b []byte) (int, error) {
+	if remaining := w.maxSize - w.body.Len(); remaining > 0 {
+		if len(b) < remaining {
+			remaining = len(b)
+		}
+		w.body.Write(b[:remaining])
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
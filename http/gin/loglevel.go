@@ -0,0 +1,57 @@
+package gin
+
+import (
+	"net/http"
+	"time"
+
+	domainErrors "github.com/Raj63/go-sdk/errors"
+	"github.com/Raj63/go-sdk/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LogLevelRequest is the request body of the log level handler to change a logging level.
+type LogLevelRequest struct {
+	// Name is the logger name whose level is changed, the base level being changed without it.
+	Name string `json:"name"`
+
+	// Level is the new level, e.g. "debug", or "reset" to remove the override of the name.
+	Level string `json:"level" binding:"required"`
+
+	// RevertAfter is the optional delay after which the level is reverted, e.g. "15m".
+	RevertAfter string `json:"revert_after"`
+}
+
+// LogLevelHandler returns a Gin handler that responds with the logging levels, see
+// logger.LevelsInfo, and changes them with a PUT LogLevelRequest body, e.g.
+//
+//	router.GET("/_log/level", LogLevelHandler(logger.Levels()))
+//	router.PUT("/_log/level", LogLevelHandler(logger.Levels()))
+func LogLevelHandler(levels *logger.Levels) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodPut {
+			var req LogLevelRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				_ = c.Error(err).SetType(gin.ErrorTypeBind)
+				return
+			}
+
+			var revertAfter time.Duration
+			if req.RevertAfter != "" {
+				d, err := time.ParseDuration(req.RevertAfter)
+				if err != nil {
+					_ = c.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+					return
+				}
+				revertAfter = d
+			}
+
+			if err := levels.Update(req.Name, req.Level, revertAfter); err != nil {
+				_ = c.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+				return
+			}
+		}
+
+		c.JSON(http.StatusOK, levels.Info())
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	healthcheck "github.com/RaMin0/gin-health-check"
+	sdkconfig "github.com/Raj63/go-sdk/config"
 	"github.com/Raj63/go-sdk/http/gin/errors"
 	"github.com/Raj63/go-sdk/http/gin/ratelimiter"
 	"github.com/Raj63/go-sdk/logger"
@@ -28,8 +29,13 @@ import (
 
 // MiddlewaresConfig is a set of middlewares related config params
 type MiddlewaresConfig struct {
+	// DebugEnabled serves the debug handlers, e.g. pprof, and the logging levels at `/_log/level`,
+	// which are changed with PUT, see LogLevelHandler.
 	DebugEnabled      bool
 	PrometheusEnabled bool
+	// DebugConfig is the application config struct that is served at `/_config`, with its
+	// secrets masked, when DebugEnabled is set. Add `?format=table` for a text table.
+	DebugConfig       interface{}
 	RateLimiterConfig struct {
 		Enabled    bool
 		Interval   time.Duration
@@ -57,10 +63,19 @@ type MiddlewaresConfig struct {
 			AllowDirectoryIndexing bool
 		}
 	}
+	// BodyLoggingOptions indicates if the request and response bodies are logged at the debug
+	// level, truncated to MaxSize bytes, see BodyLoggerMiddleware.
+	BodyLoggingOptions struct {
+		Enabled bool
+		MaxSize int
+	}
 	NewRelicOptions struct {
 		ServiceName string
-		LicenseKey  string
+		LicenseKey  string `secret:"true"`
 	}
+	// ErrorHandler indicates how the errors of the request handlers are responded, see
+	// errors.NewHandler.
+	ErrorHandler errors.HandlerConfig
 }
 
 // AddBasicHandlers will add basic handlers required by a Server.
@@ -80,6 +95,17 @@ func AddBasicHandlers(router *gin.Engine, config *MiddlewaresConfig, logger *log
 	// Setup the Health check middleware at the top
 	router.Use(healthcheck.Default())
 
+	// Setup the context logger with the request fields before the handlers that log
+	router.Use(LoggerMiddleware(logger))
+	if config.BodyLoggingOptions.Enabled {
+		router.Use(BodyLoggerMiddleware(logger, config.BodyLoggingOptions.MaxSize))
+	}
+
+	// Setup Error handler and the panic recovery whose errors it responds to before the other
+	// middlewares, so that it responds to their errors too, e.g. RateLimited
+	router.Use(errors.NewHandler(&config.ErrorHandler))
+	router.Use(errors.Recovery(logger))
+
 	// Documentation Swagger
 	{
 		router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -156,10 +182,7 @@ func AddBasicHandlers(router *gin.Engine, config *MiddlewaresConfig, logger *log
 				return c.ClientIP()
 			},
 			// config: how to respond when limiting
-			LimitedHandler: func(c *gin.Context) {
-				c.JSON(200, "Exceeds Request rate limit!!!")
-				c.Abort()
-			},
+			LimitedHandler: ratelimiter.DefaultGinLimitedHandler,
 			// config: return ratelimiter token fill interval and bucket size
 			TokenBucketConfig: func(*gin.Context) (time.Duration, int) {
 				return config.RateLimiterConfig.Interval, config.RateLimiterConfig.BucketSize
@@ -194,11 +217,32 @@ func AddBasicHandlers(router *gin.Engine, config *MiddlewaresConfig, logger *log
 		pprof.Register(router)
 	}
 
-	// Setup Error handler
-	router.Use(errors.Handler)
+	// Setup Debug effective config handler
+	if config.DebugEnabled && config.DebugConfig != nil {
+		router.GET("/_config", configDumpHandler(config.DebugConfig))
+	}
+
+	// Setup Debug log level handler to read and change the logging levels at runtime
+	if config.DebugEnabled && logger.Levels() != nil {
+		router.GET("/_log/level", LogLevelHandler(logger.Levels()))
+		router.PUT("/_log/level", LogLevelHandler(logger.Levels()))
+	}
+
 	return nil
 }
 
+func configDumpHandler(appConfig interface{}) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entries := sdkconfig.Dump(appConfig)
+		if c.Query("format") == "table" {
+			c.String(http.StatusOK, entries.Table())
+			return
+		}
+
+		c.JSON(http.StatusOK, entries)
+	}
+}
+
 func formatDate(t time.Time) string {
 	return t.Format(time.RFC822)
 }
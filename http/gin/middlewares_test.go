@@ -0,0 +1,19 @@
+package gin
+
+import (
+	"testing"
+
+	sdkconfig "github.com/Raj63/go-sdk/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MiddlewaresConfig_Dump(t *testing.T) {
+	c := &MiddlewaresConfig{}
+	c.NewRelicOptions.ServiceName = "app"
+	c.NewRelicOptions.LicenseKey = "nr-license-123"
+
+	entries := sdkconfig.Dump(c)
+
+	assert.Contains(t, entries, sdkconfig.DumpEntry{Field: "NewRelicOptions.LicenseKey", Value: sdkconfig.Mask})
+	assert.NotContains(t, entries.Table(), "nr-license-123")
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Raj63/go-sdk/errors"
 	"github.com/Raj63/go-sdk/logger"
 	"github.com/gin-gonic/gin"
 )
@@ -24,9 +25,12 @@ func DefaultGinLimitKey(c *gin.Context) string {
 	return fmt.Sprintf("pink-lady:ratelimiter:%s:%s", c.ClientIP(), c.FullPath())
 }
 
-// DefaultGinLimitedHandler 429
+// DefaultGinLimitedHandler 429, it adds a RateLimited app error to the request errors for the
+// error handler to respond with.
 func DefaultGinLimitedHandler(c *gin.Context) {
-	c.AbortWithStatus(http.StatusTooManyRequests)
+	_ = c.Error(errors.NewAppErrorWithType(errors.RateLimited))
+	c.Status(http.StatusTooManyRequests)
+	c.Abort()
 }
 
 // GinMemRatelimiter return the gin rate limiter handler func
@@ -0,0 +1,171 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Config indicates how a Logger should be initialised.
+type Config struct {
+	// Env is the environment of the application, it is logged as the `env` field and it picks the
+	// defaults of the other settings: the development ones when it is empty or "development", and
+	// the production ones otherwise. By default, it is the `APP_ENV` environment variable.
+	Env string
+
+	// Service is the name of the application, logged as the `service` field if it is set.
+	Service string
+
+	// Version is the version of the application, logged as the `version` field if it is set.
+	Version string
+
+	// InitialFields are the fields added to every log entry, along with `env`, `service` and
+	// `version`.
+	InitialFields map[string]interface{}
+
+	// Level is the minimum enabled logging level, i.e. "debug", "info", "warn", "error", "dpanic",
+	// "panic" or "fatal". By default, it is "debug" in development and "info" in production.
+	Level string
+
+	// LevelOverrides are the levels of the loggers by name, e.g. {"sql": "warn"}, see Levels.
+	LevelOverrides map[string]string
+
+	// Encoding is the encoding of the log entries, i.e. "console" or "json". By default, it is
+	// "console" in development and "json" in production.
+	Encoding string
+
+	// OutputPaths are the URLs or file paths that the log entries are written to, e.g. "stdout" or
+	// "/var/log/app.log". By default, it is ["stderr"].
+	OutputPaths []string
+
+	// ErrorOutputPaths are the URLs or file paths that the logger's internal errors are written to.
+	// By default, it is ["stderr"].
+	ErrorOutputPaths []string
+
+	// DisableCaller indicates if the log entries shouldn't be annotated with the calling function's
+	// file name and line number. By default, it is false.
+	DisableCaller bool
+
+	// DisableStacktrace indicates if the stack traces shouldn't be captured. By default, it is false.
+	DisableStacktrace bool
+
+	// StacktraceLevel is the minimum level of the log entries whose stack trace is captured. By
+	// default, it is "warn" in development and "error" in production.
+	StacktraceLevel string
+
+	// Sampling caps the number of entries with the same level and message that are logged per
+	// second: the first Initial ones and every Thereafter-th one after that. A zero Initial
+	// disables the sampling. By default, it is 100 and 100 in production and it is disabled in
+	// development.
+	Sampling *zap.SamplingConfig
+
+	// Redaction indicates how the sensitive values of the logged payloads are masked, i.e. the gRPC
+	// payloads, the SQL queries and the HTTP bodies, see Redactor.
+	Redaction RedactorConfig
+}
+
+func defaultConfig(c *Config) {
+	if c.Env == "" {
+		c.Env = os.Getenv("APP_ENV")
+	}
+
+	development := c.development()
+	if c.Level == "" {
+		c.Level = "info"
+		if development {
+			c.Level = "debug"
+		}
+	}
+
+	if c.Encoding == "" {
+		c.Encoding = "json"
+		if development {
+			c.Encoding = "console"
+		}
+	}
+
+	if len(c.OutputPaths) == 0 {
+		c.OutputPaths = []string{"stderr"}
+	}
+
+	if len(c.ErrorOutputPaths) == 0 {
+		c.ErrorOutputPaths = []string{"stderr"}
+	}
+
+	if c.StacktraceLevel == "" {
+		c.StacktraceLevel = "error"
+		if development {
+			c.StacktraceLevel = "warn"
+		}
+	}
+
+	if c.Sampling == nil && !development {
+		c.Sampling = &zap.SamplingConfig{Initial: 100, Thereafter: 100}
+	}
+}
+
+// levels returns the Levels of the base level and the level overrides.
+func (c *Config) levels(base zapcore.Level) (*Levels, error) {
+	levels := newLevels(base)
+	for name, level := range c.LevelOverrides {
+		parsed, err := zapcore.ParseLevel(level)
+		if err != nil {
+			return nil, fmt.Errorf("invalid logging level %q of the %q logger: %w", level, name, err)
+		}
+		levels.SetLevel(name, parsed, 0)
+	}
+
+	return levels, nil
+}
+
+func (c *Config) development() bool {
+	return c.Env == "" || c.Env == "development"
+}
+
+// zapConfig converts the defaulted config to the zap config and its stack trace level, the level of
+// the zap config being the base level of the Levels.
+func (c *Config) zapConfig() (zap.Config, zapcore.Level, error) {
+	zc := zap.NewProductionConfig()
+	if c.development() {
+		zc = zap.NewDevelopmentConfig()
+		zc.EncoderConfig.TimeKey = ""
+		if c.Encoding == "console" {
+			zc.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		}
+	}
+
+	level, err := zapcore.ParseLevel(c.Level)
+	if err != nil {
+		return zc, 0, fmt.Errorf("invalid logging level %q: %w", c.Level, err)
+	}
+
+	stacktraceLevel, err := zapcore.ParseLevel(c.StacktraceLevel)
+	if err != nil {
+		return zc, 0, fmt.Errorf("invalid stack trace level %q: %w", c.StacktraceLevel, err)
+	}
+
+	zc.Level = zap.NewAtomicLevelAt(level)
+	zc.Encoding = c.Encoding
+	zc.OutputPaths = c.OutputPaths
+	zc.ErrorOutputPaths = c.ErrorOutputPaths
+	zc.DisableCaller = c.DisableCaller
+	zc.DisableStacktrace = c.DisableStacktrace
+	zc.Sampling = nil
+	if c.Sampling != nil && c.Sampling.Initial > 0 {
+		zc.Sampling = c.Sampling
+	}
+
+	zc.InitialFields = map[string]interface{}{}
+	for k, v := range c.InitialFields {
+		zc.InitialFields[k] = v
+	}
+	for k, v := range map[string]string{"env": c.Env, "service": c.Service, "version": c.Version} {
+		if v != "" {
+			zc.InitialFields[k] = v
+		}
+	}
+
+	return zc, stacktraceLevel, nil
+}
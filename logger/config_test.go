@@ -0,0 +1,70 @@
+package logger_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Raj63/go-sdk/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("should log with the initial fields at the level", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		log, err := logger.New(&logger.Config{
+			Env:           "staging",
+			Service:       "school-api",
+			Version:       "1.4.2",
+			InitialFields: map[string]interface{}{"region": "ap-south-1"},
+			Level:         "warn",
+			OutputPaths:   []string{path},
+		})
+		assert.Nil(t, err)
+
+		log.Info("ignored")
+		log.Warnw("disk almost full", "usage", 91)
+		assert.Nil(t, log.Sync())
+
+		b, err := os.ReadFile(path)
+		assert.Nil(t, err)
+		lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+		assert.Len(t, lines, 1)
+
+		entry := map[string]interface{}{}
+		assert.Nil(t, json.Unmarshal([]byte(lines[0]), &entry))
+		assert.Equal(t, "warn", entry["level"])
+		assert.Equal(t, "disk almost full", entry["msg"])
+		assert.Equal(t, "staging", entry["env"])
+		assert.Equal(t, "school-api", entry["service"])
+		assert.Equal(t, "1.4.2", entry["version"])
+		assert.Equal(t, "ap-south-1", entry["region"])
+		assert.Equal(t, float64(91), entry["usage"])
+		assert.Contains(t, entry["caller"], "config_test.go")
+	})
+
+	t.Run("should not capture the stack traces when they are disabled", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		log, err := logger.New(&logger.Config{Env: "production", DisableStacktrace: true, OutputPaths: []string{path}})
+		assert.Nil(t, err)
+
+		log.Error("failed")
+		assert.Nil(t, log.Sync())
+
+		b, err := os.ReadFile(path)
+		assert.Nil(t, err)
+		assert.Contains(t, string(b), `"msg":"failed"`)
+		assert.NotContains(t, string(b), "stacktrace")
+	})
+
+	t.Run("should return an error for an invalid config", func(t *testing.T) {
+		_, err := logger.New(&logger.Config{Level: "verbose"})
+		assert.EqualError(t, err, `invalid logging level "verbose": unrecognized level: "verbose"`)
+
+		_, err = logger.New(&logger.Config{Encoding: "xml"})
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/Raj63/go-sdk/tracer"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// RequestIDKey is the field key of the request ID.
+	RequestIDKey = "request_id"
+
+	// SpanIDKey is the field key of the span ID.
+	SpanIDKey = "span_id"
+
+	// UserIDKey is the field key of the ID of the user that sends the request.
+	UserIDKey = "user_id"
+
+	// TenantIDKey is the field key of the ID of the tenant of the request.
+	TenantIDKey = "tenant_id"
+
+	// RouteKey is the field key of the route of the request, e.g. "/users/:id" or the gRPC method.
+	RouteKey = "route"
+)
+
+type contextFieldsKey struct{}
+
+type contextLoggerKey struct{}
+
+// WithContext returns a copy of the context that carries the fields along with the fields that it
+// already carries, so that they are added to the log entries of the context, see FromContext and
+// the `*Context` methods of Logger. A field replaces the context's field of the same key, e.g.
+//
+//	ctx = logger.WithContext(ctx, zap.String(logger.UserIDKey, claims.Subject))
+func WithContext(ctx context.Context, fields ...zap.Field) context.Context {
+	existing := contextFields(ctx)
+	merged := make([]zap.Field, 0, len(existing)+len(fields))
+	for _, f := range existing {
+		if !hasKey(fields, f.Key) {
+			merged = append(merged, f)
+		}
+	}
+
+	return context.WithValue(ctx, contextFieldsKey{}, append(merged, fields...))
+}
+
+// NewContext returns a copy of the context that carries the logger, see FromContext.
+func NewContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, contextLoggerKey{}, logger)
+}
+
+// FromContext returns the logger of the context, see NewContext, with the `trace_id` and `span_id`
+// of its span and the fields that it carries, see WithContext. It returns a no-op logger if the
+// context has no logger.
+func FromContext(ctx context.Context) *Logger {
+	logger, ok := ctx.Value(contextLoggerKey{}).(*Logger)
+	if !ok {
+		logger = &Logger{SugaredLogger: zap.NewNop().Sugar()}
+	}
+
+	return logger.withContext(ctx)
+}
+
+// Fields returns the `trace_id` and `span_id` of the context's span, if any, and the fields that
+// it carries, see WithContext.
+func Fields(ctx context.Context) []zap.Field {
+	fields := []zap.Field{}
+	if sc := tracer.SpanFromContext(ctx).SpanContext(); sc.HasTraceID() {
+		fields = append(fields, zap.String(traceID, sc.TraceID().String()))
+		if sc.HasSpanID() {
+			fields = append(fields, zap.String(SpanIDKey, sc.SpanID().String()))
+		}
+	}
+
+	return append(fields, contextFields(ctx)...)
+}
+
+// withContext returns the logger with the `trace_id` of the context, as the `*Context` methods
+// always log it, and the other fields of the context, see Fields.
+func (logger *Logger) withContext(ctx context.Context) *Logger {
+	fields := Fields(ctx)
+	if len(fields) == 0 || fields[0].Key != traceID {
+		fields = append([]zap.Field{zap.String(traceID, tracer.GetTraceIDFromContext(ctx))}, fields...)
+	}
+
+	return &Logger{
+		SugaredLogger: logger.Desugar().With(fields...).Sugar(),
+		levels:        logger.levels,
+		redactor:      logger.redactor,
+	}
+}
+
+func contextFields(ctx context.Context) []zap.Field {
+	fields, _ := ctx.Value(contextFieldsKey{}).([]zap.Field)
+	return fields
+}
+
+func hasKey(fields []zap.Field, key string) bool {
+	for _, f := range fields {
+		if f.Key == key {
+			return true
+		}
+	}
+
+	return false
+}
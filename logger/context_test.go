@@ -0,0 +1,41 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Raj63/go-sdk/logger"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+func TestContext(t *testing.T) {
+	t.Run("should log the fields of the context", func(t *testing.T) {
+		log, buf, writer := logger.NewTestLogger()
+
+		spanCtx := trace.SpanContextFromContext(context.Background()).
+			WithTraceID(trace.TraceID([16]byte{1})).
+			WithSpanID(trace.SpanID([8]byte{2}))
+		ctx := trace.ContextWithSpanContext(context.Background(), spanCtx)
+		ctx = logger.NewContext(ctx, log)
+		ctx = logger.WithContext(ctx, zap.String(logger.RequestIDKey, "req-1"), zap.String(logger.UserIDKey, "anonymous"))
+		ctx = logger.WithContext(ctx, zap.String(logger.UserIDKey, "u-42"))
+
+		logger.FromContext(ctx).Info("enrolled")
+		log.WarnContext(ctx, "slow")
+		writer.Flush()
+
+		fields := `{"trace_id": "01000000000000000000000000000000", "span_id": "0200000000000000", "request_id": "req-1", "user_id": "u-42"}`
+		assert.Contains(t, buf.String(), "enrolled\t"+fields+"\n")
+		assert.Contains(t, buf.String(), "slow\t"+fields+"\n")
+	})
+
+	t.Run("should return a no-op logger without a logger in the context", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			logger.FromContext(context.Background()).Info("ignored")
+		})
+		assert.Empty(t, logger.Fields(context.Background()))
+	})
+}
@@ -0,0 +1,217 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Levels controls the logging levels of a Logger at runtime: its base level and the overrides of
+// the loggers by name, e.g. the "sql" override applies to the loggers named "sql" and "sql.query",
+// see zap.Logger.Named. It is safe for concurrent use.
+type Levels struct {
+	mu        sync.RWMutex
+	base      zap.AtomicLevel
+	overrides map[string]zapcore.Level
+	reverts   map[string]*levelRevert
+}
+
+// levelRevert is a pending revert of a level to the level that it had before it was temporarily
+// set.
+type levelRevert struct {
+	timer       *time.Timer
+	previous    zapcore.Level
+	hasPrevious bool
+}
+
+// LevelsInfo describes the logging levels of a Logger.
+type LevelsInfo struct {
+	// Level is the base level.
+	Level string `json:"level"`
+
+	// Overrides are the levels of the loggers by name.
+	Overrides map[string]string `json:"overrides,omitempty"`
+}
+
+func newLevels(base zapcore.Level) *Levels {
+	return &Levels{
+		base:      zap.NewAtomicLevelAt(base),
+		overrides: map[string]zapcore.Level{},
+		reverts:   map[string]*levelRevert{},
+	}
+}
+
+// Level returns the level of the logger name, i.e. the level of its longest matching override, or
+// else the base level.
+func (l *Levels) Level(name string) zapcore.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.level(name)
+}
+
+func (l *Levels) level(name string) zapcore.Level {
+	for ; name != ""; name = parentName(name) {
+		if level, ok := l.overrides[name]; ok {
+			return level
+		}
+	}
+
+	return l.base.Level()
+}
+
+// SetLevel sets the level of the logger name, or the base level if the name is empty. A positive
+// revertAfter reverts the level to its current value once it has elapsed, e.g. to turn on the
+// debug logs for 15 minutes. Setting a temporary level again, e.g. to extend it, keeps the level
+// that it reverts to.
+func (l *Levels) SetLevel(name string, level zapcore.Level, revertAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	previous, hasPrevious := l.overrides[name]
+	if name == "" {
+		previous, hasPrevious = l.base.Level(), true
+	}
+	if pending, ok := l.reverts[name]; ok {
+		pending.timer.Stop()
+		delete(l.reverts, name)
+		previous, hasPrevious = pending.previous, pending.hasPrevious
+	}
+	l.set(name, level)
+
+	if revertAfter > 0 {
+		revert := &levelRevert{previous: previous, hasPrevious: hasPrevious}
+		revert.timer = time.AfterFunc(revertAfter, func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			// a later SetLevel or ResetLevel has replaced this revert
+			if l.reverts[name] != revert {
+				return
+			}
+			delete(l.reverts, name)
+
+			if revert.hasPrevious {
+				l.set(name, revert.previous)
+				return
+			}
+			delete(l.overrides, name)
+		})
+		l.reverts[name] = revert
+	}
+}
+
+// ResetLevel removes the override of the logger name, along with its pending revert.
+func (l *Levels) ResetLevel(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if pending, ok := l.reverts[name]; ok {
+		pending.timer.Stop()
+		delete(l.reverts, name)
+	}
+	delete(l.overrides, name)
+}
+
+// Info returns the base level and the overrides.
+func (l *Levels) Info() LevelsInfo {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	info := LevelsInfo{Level: l.base.Level().String()}
+	if len(l.overrides) > 0 {
+		info.Overrides = make(map[string]string, len(l.overrides))
+		for name, level := range l.overrides {
+			info.Overrides[name] = level.String()
+		}
+	}
+
+	return info
+}
+
+// Update parses and sets the level of the logger name, see SetLevel. The level "reset" removes the
+// override of the name, see ResetLevel.
+func (l *Levels) Update(name, level string, revertAfter time.Duration) error {
+	if name != "" && strings.EqualFold(level, "reset") {
+		l.ResetLevel(name)
+		return nil
+	}
+
+	parsed, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("invalid logging level %q: %w", level, err)
+	}
+	if revertAfter < 0 {
+		return fmt.Errorf("invalid revert delay %s", revertAfter)
+	}
+
+	l.SetLevel(name, parsed, revertAfter)
+	return nil
+}
+
+func (l *Levels) set(name string, level zapcore.Level) {
+	if name == "" {
+		l.base.SetLevel(level)
+		return
+	}
+	l.overrides[name] = level
+}
+
+// enabled reports whether the level is enabled for the logger name.
+func (l *Levels) enabled(name string, level zapcore.Level) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.level(name).Enabled(level)
+}
+
+// anyEnabled reports whether the level is enabled for any logger name.
+func (l *Levels) anyEnabled(level zapcore.Level) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.base.Enabled(level) {
+		return true
+	}
+	for _, override := range l.overrides {
+		if override.Enabled(level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parentName(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[:i]
+	}
+
+	return ""
+}
+
+// levelsCore filters the log entries by the level of their logger name.
+type levelsCore struct {
+	zapcore.Core
+	levels *Levels
+}
+
+func (c *levelsCore) Enabled(level zapcore.Level) bool {
+	return c.levels.anyEnabled(level)
+}
+
+func (c *levelsCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelsCore{c.Core.With(fields), c.levels}
+}
+
+func (c *levelsCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.levels.enabled(ent.LoggerName, ent.Level) {
+		return ce
+	}
+
+	return c.Core.Check(ent, ce)
+}
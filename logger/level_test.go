@@ -0,0 +1,79 @@
+package logger_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Raj63/go-sdk/logger"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLevels(t *testing.T) {
+	t.Run("should filter the entries by the level of their logger name", func(t *testing.T) {
+		log, buf, writer := logger.NewTestLogger()
+		levels := log.Levels()
+		levels.SetLevel("", zapcore.InfoLevel, 0)
+		levels.SetLevel("sql", zapcore.DebugLevel, 0)
+
+		log.Debug("base debug")
+		log.Named("sql").Named("query").Debug("sql debug")
+		log.Named("sqlite").Debug("sqlite debug")
+		log.Info("base info")
+		writer.Flush()
+
+		assert.NotContains(t, buf.String(), "base debug")
+		assert.Contains(t, buf.String(), "sql debug")
+		assert.NotContains(t, buf.String(), "sqlite debug")
+		assert.Contains(t, buf.String(), "base info")
+		assert.Equal(t, logger.LevelsInfo{Level: "info", Overrides: map[string]string{"sql": "debug"}}, levels.Info())
+
+		assert.Nil(t, levels.Update("sql", "reset", 0))
+		assert.Equal(t, zapcore.InfoLevel, levels.Level("sql.query"))
+		assert.NotNil(t, levels.Update("", "verbose", 0))
+	})
+
+	t.Run("should revert the level after the delay", func(t *testing.T) {
+		log, _, _ := logger.NewTestLogger()
+		levels := log.Levels()
+		levels.SetLevel("", zapcore.WarnLevel, 0)
+
+		levels.SetLevel("", zapcore.DebugLevel, 20*time.Millisecond)
+		levels.SetLevel("grpc", zapcore.DebugLevel, 20*time.Millisecond)
+		assert.Equal(t, zapcore.DebugLevel, levels.Level(""))
+
+		assert.Eventually(t, func() bool {
+			return levels.Level("") == zapcore.WarnLevel && levels.Level("grpc") == zapcore.WarnLevel
+		}, time.Second, 5*time.Millisecond)
+		assert.Empty(t, levels.Info().Overrides)
+	})
+
+	t.Run("should revert an extended temporary level to the level before it", func(t *testing.T) {
+		log, _, _ := logger.NewTestLogger()
+		levels := log.Levels()
+		levels.SetLevel("", zapcore.WarnLevel, 0)
+
+		levels.SetLevel("", zapcore.DebugLevel, time.Hour)
+		levels.SetLevel("", zapcore.DebugLevel, 20*time.Millisecond)
+		levels.SetLevel("sql", zapcore.DebugLevel, time.Hour)
+		levels.SetLevel("sql", zapcore.DebugLevel, 20*time.Millisecond)
+
+		assert.Eventually(t, func() bool {
+			return levels.Level("") == zapcore.WarnLevel && levels.Level("sql") == zapcore.WarnLevel
+		}, time.Second, 5*time.Millisecond)
+		assert.Empty(t, levels.Info().Overrides)
+	})
+
+	t.Run("should apply the level overrides of the config", func(t *testing.T) {
+		log, err := logger.New(&logger.Config{Level: "info", LevelOverrides: map[string]string{"sql": "error"}, OutputPaths: []string{"stdout"}})
+		assert.Nil(t, err)
+		assert.Equal(t, zapcore.ErrorLevel, log.Levels().Level("sql"))
+		assert.False(t, log.Desugar().Core().Enabled(zapcore.DebugLevel))
+		log.Levels().SetLevel("", zapcore.DebugLevel, 0)
+		assert.True(t, log.Desugar().Core().Enabled(zapcore.DebugLevel))
+
+		_, err = logger.New(&logger.Config{LevelOverrides: map[string]string{"sql": "loud"}})
+		assert.NotNil(t, err)
+	})
+}
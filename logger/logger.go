@@ -4,29 +4,90 @@ import (
 	"bufio"
 	"bytes"
 	"context"
-	"os"
+	"fmt"
+	"strings"
 
-	"github.com/Raj63/go-sdk/tracer"
+	"github.com/Raj63/go-sdk/errors"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-const traceID = "trace_id"
+const (
+	traceID    = "trace_id"
+	errorStack = "error_stack"
+)
 
 // Logger provides the logging functionality.
 type Logger struct {
 	*zap.SugaredLogger
+
+	levels   *Levels
+	redactor *Redactor
 }
 
-// NewLogger initializes Logger instance.
-func NewLogger() *Logger {
-	c := newLoggerConfig()
-	logger, _ := c.Build()
+// New initializes a Logger instance with the config, see Config for the defaults.
+func New(c *Config) (*Logger, error) {
+	defaultConfig(c)
+	zc, stacktraceLevel, err := c.zapConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	levels, err := c.levels(zc.Level.Level())
+	if err != nil {
+		return nil, err
+	}
+
+	// the levels filter the entries, whatever the level of the zap config
+	zc.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
+	opts := []zap.Option{
+		zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return &levelsCore{core, levels}
+		}),
+	}
+	if !c.DisableStacktrace {
+		opts = append(opts, zap.AddStacktrace(stacktraceLevel))
+	}
+	logger, err := zc.Build(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build the logger: %w", err)
+	}
 
 	return &Logger{
 		SugaredLogger: logger.Sugar(),
+		levels:        levels,
+		redactor:      NewRedactor(&c.Redaction),
+	}, nil
+}
+
+// Levels returns the controller of the logging levels, to change them at runtime.
+func (logger *Logger) Levels() *Levels {
+	return logger.levels
+}
+
+// Redactor returns the redactor of the logged payloads, see Config.Redaction.
+func (logger *Logger) Redactor() *Redactor {
+	if logger.redactor == nil {
+		return defaultRedactor
+	}
+
+	return logger.redactor
+}
+
+// NewLogger initializes Logger instance with the default config of the `APP_ENV` environment,
+// without the caller annotation in development, see New. It panics if the logger can't be built.
+func NewLogger() *Logger {
+	c := &Config{}
+	defaultConfig(c)
+	c.DisableCaller = c.development()
+
+	logger, err := New(c)
+	if err != nil {
+		panic(err)
 	}
+
+	return logger
 }
 
 // NewTestLogger initializes a test Logger instance that is useful for testing purpose.
@@ -34,67 +95,89 @@ func NewTestLogger() (*Logger, *bytes.Buffer, *bufio.Writer) {
 	var buffer bytes.Buffer
 	writer := bufio.NewWriter(&buffer)
 	c := newLoggerConfig()
+	levels := newLevels(zapcore.DebugLevel)
 
 	return &Logger{
 		SugaredLogger: zap.New(
-			zapcore.NewCore(
-				zapcore.NewConsoleEncoder(c.EncoderConfig),
-				zapcore.AddSync(writer),
-				zapcore.DebugLevel,
-			),
+			&levelsCore{
+				zapcore.NewCore(
+					zapcore.NewConsoleEncoder(c.EncoderConfig),
+					zapcore.AddSync(writer),
+					zapcore.DebugLevel,
+				),
+				levels,
+			},
 		).Sugar(),
+		levels: levels,
 	}, &buffer, writer
 }
 
-// DebugContext uses fmt.Sprint to construct and log a message with the `trace_id` found in the context.
+// WithError adds the error's message and, if any, the stack trace of its origin to the logging
+// context, see ErrorStack.
+func (logger *Logger) WithError(err error) *Logger {
+	return &Logger{
+		SugaredLogger: logger.Desugar().With(zap.String("error", err.Error()), ErrorStack(err)).Sugar(),
+		levels:        logger.levels,
+		redactor:      logger.redactor,
+	}
+}
+
+// ErrorStack returns the `error_stack` field with the stack trace of the error's origin, which the
+// app errors capture when it is enabled, see errors.SetStackTrace. The field is skipped if the error
+// has no stack trace.
+func ErrorStack(err error) zap.Field {
+	stack := errors.Stack(err)
+	if len(stack) == 0 {
+		return zap.Skip()
+	}
+
+	return zap.String(errorStack, strings.TrimPrefix(fmt.Sprintf("%+v", stack), "\n"))
+}
+
+// DebugContext uses fmt.Sprint to construct and log a message with the fields of the context, see Fields.
 func (logger *Logger) DebugContext(ctx context.Context, args ...interface{}) {
-	logger.With(traceID, tracer.GetTraceIDFromContext(ctx)).Debug(args...)
+	logger.withContext(ctx).Debug(args...)
 }
 
-// DebugfContext uses fmt.Sprintf to log a templated message with the `trace_id` found in the context.
+// DebugfContext uses fmt.Sprintf to log a templated message with the fields of the context, see Fields.
 func (logger *Logger) DebugfContext(ctx context.Context, template string, args ...interface{}) {
-	logger.With(traceID, tracer.GetTraceIDFromContext(ctx)).Debugf(template, args...)
+	logger.withContext(ctx).Debugf(template, args...)
 }
 
-// ErrorContext uses fmt.Sprint to construct and log a message with the `trace_id` found in the context.
+// ErrorContext uses fmt.Sprint to construct and log a message with the fields of the context, see Fields.
 func (logger *Logger) ErrorContext(ctx context.Context, args ...interface{}) {
-	logger.With(traceID, tracer.GetTraceIDFromContext(ctx)).Error(args...)
+	logger.withContext(ctx).Error(args...)
 }
 
-// ErrorfContext uses fmt.Sprintf to log a templated message with the `trace_id` found in the context.
+// ErrorfContext uses fmt.Sprintf to log a templated message with the fields of the context, see Fields.
 func (logger *Logger) ErrorfContext(ctx context.Context, template string, args ...interface{}) {
-	logger.With(traceID, tracer.GetTraceIDFromContext(ctx)).Errorf(template, args...)
+	logger.withContext(ctx).Errorf(template, args...)
 }
 
-// InfoContext uses fmt.Sprint to construct and log a message with the `trace_id` found in the context.
+// InfoContext uses fmt.Sprint to construct and log a message with the fields of the context, see Fields.
 func (logger *Logger) InfoContext(ctx context.Context, args ...interface{}) {
-	logger.With(traceID, tracer.GetTraceIDFromContext(ctx)).Info(args...)
+	logger.withContext(ctx).Info(args...)
 }
 
-// InfofContext uses fmt.Sprintf to log a templated message with the `trace_id` found in the context.
+// InfofContext uses fmt.Sprintf to log a templated message with the fields of the context, see Fields.
 func (logger *Logger) InfofContext(ctx context.Context, template string, args ...interface{}) {
-	logger.With(traceID, tracer.GetTraceIDFromContext(ctx)).Infof(template, args...)
+	logger.withContext(ctx).Infof(template, args...)
 }
 
-// WarnContext uses fmt.Sprint to construct and log a message with the `trace_id` found in the context.
+// WarnContext uses fmt.Sprint to construct and log a message with the fields of the context, see Fields.
 func (logger *Logger) WarnContext(ctx context.Context, args ...interface{}) {
-	logger.With(traceID, tracer.GetTraceIDFromContext(ctx)).Warn(args...)
+	logger.withContext(ctx).Warn(args...)
 }
 
-// WarnfContext uses fmt.Sprintf to log a templated message with the `trace_id` found in the context.
+// WarnfContext uses fmt.Sprintf to log a templated message with the fields of the context, see Fields.
 func (logger *Logger) WarnfContext(ctx context.Context, template string, args ...interface{}) {
-	logger.With(traceID, tracer.GetTraceIDFromContext(ctx)).Warnf(template, args...)
+	logger.withContext(ctx).Warnf(template, args...)
 }
 
 func newLoggerConfig() zap.Config {
-	c := zap.NewDevelopmentConfig()
-	c.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
-	c.EncoderConfig.TimeKey = ""
-	c.EncoderConfig.CallerKey = ""
-
-	if os.Getenv("APP_ENV") != "" && os.Getenv("APP_ENV") != "development" {
-		c = zap.NewProductionConfig()
-	}
+	c := &Config{}
+	defaultConfig(c)
+	zc, _, _ := c.zapConfig()
 
-	return c
+	return zc
 }
@@ -0,0 +1,435 @@
+package logger
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// RedactedMask is the default value that the sensitive values are replaced with.
+const RedactedMask = "[REDACTED]"
+
+// RedactRule masks the matches of a pattern in any logged string.
+type RedactRule struct {
+	// Name is the name of the rule, e.g. "email".
+	Name string
+
+	// Pattern matches the sensitive values.
+	Pattern *regexp.Regexp
+
+	// Replacement replaces the matches, see regexp.Regexp.ReplaceAllString. By default, it is the
+	// mask of the redactor.
+	Replacement string
+
+	// Valid reports whether a match is a sensitive value, e.g. a card number with a valid checksum.
+	// By default, every match is.
+	Valid func(match string) bool
+}
+
+var (
+	// CardNumberRule masks the payment card numbers, i.e. 13 to 19 digits that may be grouped by
+	// spaces or dashes and that pass the Luhn check, unlike most timestamps and IDs.
+	CardNumberRule = RedactRule{Name: "card_number", Pattern: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), Valid: luhn}
+
+	// EmailRule masks the email addresses.
+	EmailRule = RedactRule{Name: "email", Pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)}
+
+	// JWTRule masks the JSON web tokens.
+	JWTRule = RedactRule{Name: "jwt", Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)}
+)
+
+// DefaultRedactFields are the default names of the sensitive fields, see RedactorConfig.
+var DefaultRedactFields = []string{
+	"password", "passwd", "secret", "token", "authorization", "apikey", "privatekey", "cardnumber",
+	"cvv", "otp", "pin",
+}
+
+// RedactorConfig indicates how a Redactor should be initialised.
+type RedactorConfig struct {
+	// Fields are the names of the sensitive fields whose values are masked. A field name matches
+	// if, case-insensitively, some of its consecutive words are one of them, e.g. "new_password",
+	// "apiKey" and "X-Auth-Token", but not "shipping". By default, it is DefaultRedactFields.
+	Fields []string
+
+	// Rules are the patterns masked in any string. By default, they are CardNumberRule, EmailRule
+	// and JWTRule.
+	Rules []RedactRule
+
+	// Mask is the value that the sensitive values are replaced with. By default, it is
+	// RedactedMask.
+	Mask string
+}
+
+// Redactor masks the sensitive values of the logged payloads: the values of the sensitive fields,
+// the proto fields with the `debug_redact` option and the matches of the rules in any string.
+type Redactor struct {
+	fields []string
+	rules  []RedactRule
+	mask   string
+}
+
+var defaultRedactor = NewRedactor(&RedactorConfig{})
+
+// NewRedactor initialises a Redactor.
+func NewRedactor(c *RedactorConfig) *Redactor {
+	defaultRedactorConfig(c)
+
+	r := &Redactor{mask: c.Mask}
+	for _, field := range c.Fields {
+		r.fields = append(r.fields, normalizeFieldName(field))
+	}
+	for _, rule := range c.Rules {
+		if rule.Replacement == "" {
+			rule.Replacement = c.Mask
+		}
+		r.rules = append(r.rules, rule)
+	}
+
+	return r
+}
+
+func defaultRedactorConfig(c *RedactorConfig) {
+	if c.Fields == nil {
+		c.Fields = DefaultRedactFields
+	}
+
+	if c.Rules == nil {
+		c.Rules = []RedactRule{JWTRule, EmailRule, CardNumberRule}
+	}
+
+	if c.Mask == "" {
+		c.Mask = RedactedMask
+	}
+}
+
+// IsSensitive reports whether the field name is sensitive.
+func (r *Redactor) IsSensitive(name string) bool {
+	words := fieldNameWords(name)
+	for i := range words {
+		part := ""
+		for _, word := range words[i:] {
+			part += word
+			for _, field := range r.fields {
+				if part == field {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// String masks the matches of the rules in the string.
+func (r *Redactor) String(s string) string {
+	for _, rule := range r.rules {
+		if rule.Valid == nil {
+			s = rule.Pattern.ReplaceAllString(s, rule.Replacement)
+			continue
+		}
+
+		rule := rule
+		s = rule.Pattern.ReplaceAllStringFunc(s, func(match string) string {
+			if !rule.Valid(match) {
+				return match
+			}
+			return rule.Pattern.ReplaceAllString(match, rule.Replacement)
+		})
+	}
+
+	return s
+}
+
+// luhn reports whether the digits of the number, ignoring the other characters, pass the Luhn
+// check of the card numbers.
+func luhn(number string) bool {
+	sum, double := 0, false
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+// Value returns a copy of the decoded JSON value, e.g. a map[string]interface{}, with the values of
+// its sensitive fields masked and the rules applied to its strings.
+func (r *Redactor) Value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for k, value := range v {
+			if r.IsSensitive(k) {
+				redacted[k] = r.mask
+				continue
+			}
+			redacted[k] = r.Value(value)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, value := range v {
+			redacted[i] = r.Value(value)
+		}
+		return redacted
+	case string:
+		return r.String(v)
+	}
+
+	return v
+}
+
+// JSON returns the JSON document redacted, see Value. A document that isn't valid JSON, e.g. a
+// truncated one or a form, is redacted as a text, see Text.
+func (r *Redactor) JSON(b []byte) []byte {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return []byte(r.Text(string(b)))
+	}
+
+	redacted, err := json.Marshal(r.Value(v))
+	if err != nil {
+		return []byte(r.Text(string(b)))
+	}
+
+	return redacted
+}
+
+// textField matches the `"name": "value"`, `"name": value` and `name=value` pairs of a text.
+var textField = regexp.MustCompile(`("([^"\\]+)"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^\s,}\]]+)|\b([A-Za-z0-9_.-]+)=([^&\s]*)`)
+
+// Text masks the values of the sensitive fields of a text that isn't structured, e.g. a truncated
+// JSON document or a form, and the matches of the rules.
+func (r *Redactor) Text(s string) string {
+	s = textField.ReplaceAllStringFunc(s, func(pair string) string {
+		m := textField.FindStringSubmatch(pair)
+		switch {
+		case m[2] != "" && r.IsSensitive(m[2]):
+			return m[1] + `"` + r.mask + `"`
+		case m[4] != "" && r.IsSensitive(m[4]):
+			return m[4] + "=" + r.mask
+		}
+		return pair
+	})
+
+	return r.String(s)
+}
+
+// Proto returns a copy of the proto message whose sensitive fields, by name or by the
+// `debug_redact` field option, and sensitive string map keys, e.g. of a google.protobuf.Struct, are
+// masked, or cleared if they aren't strings, and whose strings have the rules applied, e.g.
+//
+//	string password = 2 [debug_redact = true];
+func (r *Redactor) Proto(m proto.Message) proto.Message {
+	clone := proto.Clone(m)
+	r.redactMessage(clone.ProtoReflect())
+
+	return clone
+}
+
+func (r *Redactor) redactMessage(m protoreflect.Message) {
+	type field struct {
+		fd    protoreflect.FieldDescriptor
+		value protoreflect.Value
+	}
+
+	// the message is changed after ranging over its fields
+	var fields []field
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		fields = append(fields, field{fd, v})
+		return true
+	})
+
+	for _, f := range fields {
+		fd := f.fd
+		if r.isSensitiveField(fd) {
+			if fd.Kind() == protoreflect.StringKind && fd.Cardinality() != protoreflect.Repeated {
+				m.Set(fd, protoreflect.ValueOfString(r.mask))
+				continue
+			}
+			m.Clear(fd)
+			continue
+		}
+
+		switch {
+		case fd.IsList():
+			list := f.value.List()
+			for i := 0; i < list.Len(); i++ {
+				list.Set(i, r.redactValue(fd, list.Get(i)))
+			}
+		case fd.IsMap():
+			values := f.value.Map()
+			values.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+				if fd.MapKey().Kind() == protoreflect.StringKind && r.IsSensitive(k.String()) {
+					r.maskMapValue(values, k, fd.MapValue())
+					return true
+				}
+				values.Set(k, r.redactValue(fd.MapValue(), v))
+				return true
+			})
+		default:
+			m.Set(fd, r.redactValue(fd, f.value))
+		}
+	}
+}
+
+// redactValue redacts a singular value of the field: a message or a string.
+func (r *Redactor) redactValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) protoreflect.Value {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		r.redactMessage(v.Message())
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(r.String(v.String()))
+	}
+
+	return v
+}
+
+// maskMapValue masks the value of a sensitive map key, e.g. of a google.protobuf.Struct, or removes
+// it if it can't be masked.
+func (r *Redactor) maskMapValue(values protoreflect.Map, k protoreflect.MapKey, fd protoreflect.FieldDescriptor) {
+	switch {
+	case fd.Kind() == protoreflect.StringKind:
+		values.Set(k, protoreflect.ValueOfString(r.mask))
+	case fd.Message() != nil && fd.Message().FullName() == "google.protobuf.Value":
+		v := values.NewValue()
+		v.Message().Set(fd.Message().Fields().ByName("string_value"), protoreflect.ValueOfString(r.mask))
+		values.Set(k, v)
+	default:
+		values.Clear(k)
+	}
+}
+
+func (r *Redactor) isSensitiveField(fd protoreflect.FieldDescriptor) bool {
+	if opts, ok := fd.Options().(*descriptorpb.FieldOptions); ok && opts.GetDebugRedact() {
+		return true
+	}
+
+	return r.IsSensitive(string(fd.Name()))
+}
+
+// sqlColumnPlaceholder matches the `column = ?` or `column = $1` comparisons and assignments.
+var sqlColumnPlaceholder = regexp.MustCompile(`(?i)([a-z_][a-z0-9_]*)"?\s*=\s*(\?|\$\d+)`)
+
+// sqlInsert matches the columns and the values of an `INSERT INTO table (columns) VALUES (values)`.
+var sqlInsert = regexp.MustCompile(`(?is)insert\s+into\s+[^(]+\(([^)]*)\)\s*values\s*\(([^)]*)\)`)
+
+// SQLArgs returns a copy of the arguments of the SQL query with the arguments of the sensitive
+// columns masked, e.g. the first argument of `UPDATE users SET password = $1 WHERE id = $2`, and
+// the rules applied to the string arguments.
+func (r *Redactor) SQLArgs(query string, args []interface{}) []interface{} {
+	sensitive := map[int]bool{}
+	mark := func(column, placeholder string, position int) {
+		if !r.IsSensitive(strings.Trim(column, "`\" ")) {
+			return
+		}
+		if strings.HasPrefix(placeholder, "$") {
+			if n, ok := parsePositional(placeholder); ok {
+				sensitive[n-1] = true
+			}
+			return
+		}
+		sensitive[position] = true
+	}
+
+	// the position of each `?` placeholder in the query by its offset
+	positions := map[int]int{}
+	for i := range query {
+		if query[i] == '?' {
+			positions[i] = len(positions)
+		}
+	}
+
+	for _, m := range sqlColumnPlaceholder.FindAllStringSubmatchIndex(query, -1) {
+		mark(query[m[2]:m[3]], query[m[4]:m[5]], positions[m[4]])
+	}
+
+	if m := sqlInsert.FindStringSubmatchIndex(query); m != nil {
+		columns := strings.Split(query[m[2]:m[3]], ",")
+		values := strings.Split(query[m[4]:m[5]], ",")
+		offset := m[4]
+		for i, value := range values {
+			placeholder := strings.TrimSpace(value)
+			if i < len(columns) {
+				mark(columns[i], placeholder, positions[offset+strings.Index(value, placeholder)])
+			}
+			offset += len(value) + 1
+		}
+	}
+
+	redacted := make([]interface{}, len(args))
+	for i, arg := range args {
+		if sensitive[i] {
+			redacted[i] = r.mask
+			continue
+		}
+		if s, ok := arg.(string); ok {
+			arg = r.String(s)
+		}
+		redacted[i] = arg
+	}
+
+	return redacted
+}
+
+func parsePositional(placeholder string) (int, bool) {
+	n := 0
+	for _, c := range strings.TrimPrefix(placeholder, "$") {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int(c-'0')
+	}
+
+	return n, n > 0
+}
+
+func normalizeFieldName(name string) string {
+	return strings.Join(fieldNameWords(name), "")
+}
+
+// fieldNameWords splits the field name into its lowercase words, e.g. "X-Auth-Token" and
+// "xAuthToken" into "x", "auth" and "token".
+func fieldNameWords(name string) []string {
+	var words []string
+	word := []rune{}
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+
+	runes := []rune(name)
+	for i, c := range runes {
+		switch {
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			flush()
+			continue
+		case unicode.IsUpper(c) && i > 0 && (unicode.IsLower(runes[i-1]) ||
+			(i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1]))):
+			flush()
+		}
+		word = append(word, c)
+	}
+	flush()
+
+	return words
+}
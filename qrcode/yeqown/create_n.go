@@ -1,32 +1,30 @@
 package yeqown
 
 import (
-	"fmt"
-
+	"github.com/Raj63/go-sdk/errors"
 	"github.com/Raj63/go-sdk/qrcode"
 )
 
-// CreateN implements qrcode.QRCode
+// CreateN implements qrcode.QRCode, the products are created concurrently and the failures don't
+// stop the creation of the other products: the results are in the order of the products, a failed
+// product having an empty result, along with an *errors.Multi of the failures indexed by product.
 func (y *yeqownQrcode) CreateN(input []qrcode.ProductInfo) ([]qrcode.QRResult, error) {
 	numInput := len(input)
 	results := make([]qrcode.QRResult, numInput)
 	resultCh := make(chan struct {
+		index  int
 		result qrcode.QRResult
 		err    error
-	})
-	doneCh := make(chan struct{})
-	defer close(resultCh)
+	}, numInput)
 
 	// Define the worker function that runs concurrently for each input product
 	worker := func(index int) {
 		qrResult, err := y.Create(input[index])
-		select {
-		case resultCh <- struct {
+		resultCh <- struct {
+			index  int
 			result qrcode.QRResult
 			err    error
-		}{qrResult, err}:
-		case <-doneCh:
-		}
+		}{index, qrResult, err}
 	}
 
 	// Launch a goroutine for each input product
@@ -35,18 +33,15 @@ func (y *yeqownQrcode) CreateN(input []qrcode.ProductInfo) ([]qrcode.QRResult, e
 	}
 
 	// Collect results from the result channel
+	errs := &errors.Multi{}
 	for i := 0; i < numInput; i++ {
-		select {
-		case res := <-resultCh:
-			if res.err != nil {
-				close(doneCh) // stop all goroutines by closing doneCh
-				return nil, res.err
-			}
-			results[i] = res.result
-		case <-doneCh:
-			return nil, fmt.Errorf("stopped all the go routines")
+		res := <-resultCh
+		if res.err != nil {
+			errs.Add(res.index, res.err)
+			continue
 		}
+		results[res.index] = res.result
 	}
 
-	return results, nil
+	return results, errs.ErrorOrNil()
 }
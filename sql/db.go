@@ -12,7 +12,6 @@ import (
 	"github.com/jmoiron/sqlx"
 	sqldblogger "github.com/simukti/sqldb-logger"
 	"github.com/simukti/sqldb-logger/logadapter/zapadapter"
-	"go.uber.org/zap"
 )
 
 // DB is a wrapper around sqlx.DB
@@ -59,10 +58,10 @@ func (db *DB) Open() error {
 
 	masterDB, err := sqlx.Open(driverName, db.config.URI)
 	if err != nil {
-		return fmt.Errorf("unable to connect to '%s', error: %s", db.config.URI, err.Error())
+		return fmt.Errorf("unable to connect to the %s database: %w", db.config.DriverName, err)
 	}
 
-	wrappedMasterDB, err := newDBWithLogger(masterDB.DriverName(), db.config.URI, db.config, masterDB.Driver(), db.logger.Desugar())
+	wrappedMasterDB, err := newDBWithLogger(masterDB.DriverName(), db.config.URI, db.config, masterDB.Driver(), db.logger)
 	if err != nil {
 		return err
 	}
@@ -86,23 +85,23 @@ func (db *DB) Config() *Config {
 	return db.config
 }
 
-// Conn return the database connection
+// Conn return the database connection, its failures are app errors, see ConvertError.
 func (db *DB) Conn(ctx context.Context) (*sql.Conn, error) {
 	// get master connections
 	masterConn, err := db.master.Conn(ctx)
 	if err != nil {
-		return nil, err
+		return nil, ConvertError(err)
 	}
 
 	return masterConn, nil
 }
 
-func newDBWithLogger(driverName, uri string, config *Config, driver driver.Driver, logger *zap.Logger) (*sqlx.DB, error) {
+func newDBWithLogger(driverName, uri string, config *Config, driver driver.Driver, l *logger.Logger) (*sqlx.DB, error) {
 	dbWithLogger := sqlx.NewDb(
 		sqldblogger.OpenDriver(
 			uri,
 			driver,
-			zapadapter.New(logger),
+			&redactingLogger{zapadapter.New(l.Desugar()), l.Redactor()},
 			sqldblogger.WithIncludeStartTime(true),
 			sqldblogger.WithTimeFormat(sqldblogger.TimeFormatRFC3339),
 			logLevel,
@@ -110,7 +109,7 @@ func newDBWithLogger(driverName, uri string, config *Config, driver driver.Drive
 		driverName,
 	)
 	if dbWithLogger == nil {
-		return nil, fmt.Errorf("unable to create sqlx.DB with logger for '%s'", uri)
+		return nil, fmt.Errorf("unable to create sqlx.DB with logger for the %s database", config.DriverName)
 	}
 
 	dbWithLogger.SetConnMaxIdleTime(config.ConnMaxIdleTime)
@@ -118,7 +117,7 @@ func newDBWithLogger(driverName, uri string, config *Config, driver driver.Drive
 	dbWithLogger.SetMaxIdleConns(config.MaxIdleConns)
 	dbWithLogger.SetMaxOpenConns(config.MaxOpenConns)
 	if err := dbWithLogger.Ping(); err != nil {
-		return nil, fmt.Errorf("unable to ping '%s', error: %s", uri, err.Error())
+		return nil, ConvertError(fmt.Errorf("unable to ping the %s database: %w", config.DriverName, err))
 	}
 
 	return dbWithLogger, nil
@@ -127,11 +126,12 @@ func newDBWithLogger(driverName, uri string, config *Config, driver driver.Drive
 // Ping verifies the connections to the master/replica databases are still alive, establishing a
 // connection if necessary.
 //
-// Ping uses context.Background internally; to specify the context, use PingContext.
+// Ping uses context.Background internally; to specify the context, use PingContext. Its failures
+// are app errors, see ConvertError.
 func (db *DB) Ping() error {
 	err := db.master.Ping()
 	if err != nil {
-		return fmt.Errorf("unable to ping '%s', error: %s", db.config.URI, err.Error())
+		return ConvertError(fmt.Errorf("unable to ping the %s database: %w", db.config.DriverName, err))
 	}
 
 	return nil
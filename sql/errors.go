@@ -0,0 +1,36 @@
+package sql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+
+	domainErrors "github.com/Raj63/go-sdk/errors"
+)
+
+// ConvertError converts the database errors to app errors: sql.ErrNoRows to NotFound, the context
+// errors to Timeout and Canceled, the connection failures to Unavailable and the other errors to
+// RepositoryError. The app errors and nil are returned as-is.
+func ConvertError(err error) error {
+	var appErr *domainErrors.AppError
+	if err == nil || errors.As(err, &appErr) {
+		return err
+	}
+
+	if appErr := domainErrors.FromContextError(err); appErr != nil {
+		return appErr
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return domainErrors.NewAppError(err, domainErrors.NotFound)
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return domainErrors.NewAppError(err, domainErrors.Timeout)
+	case netErr != nil, errors.Is(err, driver.ErrBadConn), errors.Is(err, sql.ErrConnDone):
+		return domainErrors.NewAppError(err, domainErrors.Unavailable)
+	}
+
+	return domainErrors.NewAppError(err, domainErrors.RepositoryError)
+}
@@ -0,0 +1,29 @@
+package sql
+
+import (
+	"context"
+
+	"github.com/Raj63/go-sdk/logger"
+
+	sqldblogger "github.com/simukti/sqldb-logger"
+)
+
+// redactingLogger masks the sensitive values of the logged queries and their arguments before
+// passing them to the wrapped logger, see logger.Redactor.
+type redactingLogger struct {
+	sqldblogger.Logger
+	redactor *logger.Redactor
+}
+
+func (l *redactingLogger) Log(ctx context.Context, level sqldblogger.Level, msg string, data map[string]interface{}) {
+	query, _ := data["query"].(string)
+	if query != "" {
+		data["query"] = l.redactor.String(query)
+	}
+
+	if args, ok := data["args"].([]interface{}); ok {
+		data["args"] = l.redactor.SQLArgs(query, args)
+	}
+
+	l.Logger.Log(ctx, level, msg, data)
+}